@@ -26,7 +26,7 @@ func GetSSMParameter(parameterName, region string) (string, error) {
 	return *result.Parameter.Value, err
 }
 
-// GetSSMParametersByPath returns values from the parameters store matching the path
+// GetSSMParametersByPath returns values from the parameters store matching the path (all pages are fetched)
 func GetSSMParametersByPath(path, region string) (result []*ssm.Parameter, err error) {
 	session, err := CreateAwsSession(region, "")
 	if err != nil {
@@ -34,17 +34,21 @@ func GetSSMParametersByPath(path, region string) (result []*ssm.Parameter, err e
 	}
 
 	svc := ssm.New(session)
-
-	response, err := svc.GetParametersByPath(&ssm.GetParametersByPathInput{
+	input := &ssm.GetParametersByPathInput{
 		Path:           aws.String(path),
 		Recursive:      aws.Bool(true),
 		WithDecryption: aws.Bool(true),
-	})
-
-	if err != nil {
-		return
 	}
 
-	result = response.Parameters
-	return
+	for {
+		var response *ssm.GetParametersByPathOutput
+		if response, err = svc.GetParametersByPath(input); err != nil {
+			return
+		}
+		result = append(result, response.Parameters...)
+		if response.NextToken == nil || *response.NextToken == "" {
+			return
+		}
+		input.NextToken = response.NextToken
+	}
 }
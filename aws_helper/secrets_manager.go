@@ -0,0 +1,26 @@
+package aws_helper
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// GetSecretValue returns the current value of a secret stored in AWS Secrets Manager
+func GetSecretValue(secretID, region string) (string, error) {
+	session, err := CreateAwsSession(region, "")
+	if err != nil {
+		return "", err
+	}
+
+	result, err := secretsmanager.New(session).GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if result.SecretString != nil {
+		return *result.SecretString, nil
+	}
+	return string(result.SecretBinary), nil
+}
@@ -67,6 +67,15 @@ func parseTerragruntOptionsFromArgs(args []string) (*options.TerragruntOptions,
 		return
 	}
 
+	parseList := func(argName string, defaultValue string) (result []string) {
+		if err == nil {
+			if result, err = parseStringListArg(args, argName); err == nil && len(result) == 0 {
+				result = util.RemoveElementFromList(filepath.SplitList(defaultValue), "")
+			}
+		}
+		return
+	}
+
 	workingDir := parse(optWorkingDir, currentDir)
 	terragruntConfigPath := parse(optTerragruntConfig, os.Getenv(options.EnvConfig), config.DefaultConfigPath(workingDir))
 	terraformPath := parse(optTerragruntTFPath, os.Getenv(options.EnvTFPath), "terraform")
@@ -78,6 +87,16 @@ func parseTerragruntOptionsFromArgs(args []string) (*options.TerragruntOptions,
 	ignoreDependencyErrors := parseBooleanArg(args, OptTerragruntIgnoreDependencyErrors, false)
 	flushDelay := parse(OptFlushDelay, os.Getenv(options.EnvFlushDelay), "60s")
 	nbWorkers := parse(OptNbWorkers, os.Getenv(options.EnvWorkers), "10")
+	reportFile := parse(OptReport, os.Getenv(options.EnvReport))
+	resumeRunID := parse(OptResume)
+	changedSince := parse(OptChangedSince)
+	includeDirs := parseList(OptIncludeDir, os.Getenv(options.EnvIncludeDir))
+	excludeDirs := parseList(OptExcludeDir, os.Getenv(options.EnvExcludeDir))
+	moduleTimeout := parse(OptModuleTimeout)
+	deadline := parse(OptDeadline)
+	groupLimits := parseList(OptConcurrencyLimit, "")
+	planDir := parse(OptPlanDir)
+	cacheTTL := parse(OptCacheTTL, os.Getenv(options.EnvCacheTTL))
 
 	if err != nil {
 		return nil, err
@@ -94,6 +113,17 @@ func parseTerragruntOptionsFromArgs(args []string) (*options.TerragruntOptions,
 	opts.IgnoreDependencyErrors = ignoreDependencyErrors
 	opts.AwsProfile = awsProfile
 	opts.ApprovalHandler = approvalHandler
+	opts.ResumeRunID = resumeRunID
+	opts.ChangedSince = changedSince
+	opts.ChangedWithDependents = parseBooleanArg(args, OptChangedDependents, false)
+	opts.IncludeDirs = includeDirs
+	opts.ExcludeDirs = excludeDirs
+	opts.IncludeDependencies = parseBooleanArg(args, OptIncludeDependencies, false)
+	opts.FailFastInterrupt = parseBooleanArg(args, OptFailFastInterrupt, false)
+	opts.FailFast = opts.FailFastInterrupt || parseBooleanArg(args, OptFailFast, false)
+	opts.AllowDestroy = parseBooleanArg(args, OptAllowDestroy, false)
+	opts.InferDependencies = parseBooleanArg(args, OptInferDependencies, false)
+	opts.StrictConfig = !parseBooleanArg(args, OptNonStrictConfig, false)
 
 	if opts.RefreshOutputDelay, err = time.ParseDuration(flushDelay); err != nil {
 		return nil, fmt.Errorf("Refresh delay must be expressed with unit (i.e. 45s)")
@@ -103,6 +133,56 @@ func parseTerragruntOptionsFromArgs(args []string) (*options.TerragruntOptions,
 		return nil, fmt.Errorf("Number of workers must be expressed as integer")
 	}
 
+	if moduleTimeout != "" {
+		if opts.ModuleTimeout, err = time.ParseDuration(moduleTimeout); err != nil || opts.ModuleTimeout < 0 {
+			return nil, fmt.Errorf("Module timeout must be expressed as a positive duration with unit (i.e. 30m)")
+		}
+	}
+
+	if cacheTTL != "" {
+		// The results of the expensive calls are also kept on disk to be reused by the following runs
+		ttl, err := time.ParseDuration(cacheTTL)
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("Cache TTL must be expressed as a positive duration with unit (i.e. 1h)")
+		}
+		opts.Cache = options.NewRunCache(util.GetTempDownloadFolder("terragrunt-cache", "memoize"), ttl)
+	}
+
+	if deadline != "" {
+		// The deadline could be expressed as a duration from now or as an absolute time
+		if duration, err := time.ParseDuration(deadline); err == nil {
+			opts.Deadline = time.Now().Add(duration)
+		} else if opts.Deadline, err = time.Parse(time.RFC3339, deadline); err != nil {
+			return nil, fmt.Errorf("Deadline must be expressed as a duration with unit (i.e. 2h) or as a RFC3339 time (i.e. 2018-01-01T20:00:00Z)")
+		}
+	}
+
+	for _, groupLimit := range groupLimits {
+		group, value, err := util.SplitEnvVariable(groupLimit)
+		limit, convErr := strconv.Atoi(value)
+		if err != nil || convErr != nil || limit <= 0 {
+			return nil, fmt.Errorf("Concurrency limit must be expressed as group=limit where limit is a positive integer (%s)", groupLimit)
+		}
+		if opts.GroupLimits == nil {
+			opts.GroupLimits = make(map[string]int, len(groupLimits))
+		}
+		opts.GroupLimits[group] = limit
+	}
+
+	if reportFile != "" {
+		// The report file is made absolute since each module of a stack is executed in its own folder
+		if opts.ReportFile, err = util.CanonicalPath(reportFile, currentDir); err != nil {
+			return nil, err
+		}
+	}
+
+	if planDir != "" {
+		// The plan folder is made absolute since each module of a stack is executed in its own folder
+		if opts.PlanDir, err = util.CanonicalPath(planDir, currentDir); err != nil {
+			return nil, err
+		}
+	}
+
 	level, err := util.InitLogging(loggingLevel, logging.NOTICE, !util.ListContainsElement(opts.TerraformCliArgs, "-no-color"))
 	os.Setenv(options.EnvLoggingLevel, fmt.Sprintf("%d", level))
 	os.Setenv(options.EnvTFPath, terraformPath)
@@ -169,6 +249,51 @@ func filterVarsAndVarFiles(command string, terragruntOptions *options.Terragrunt
 
 	// We must remove the -var and -var-file arguments because they are not needed by the terraform command
 	// but they may have been supplied by the user to help determine the current content
+	return removeVarsAndVarFiles(args), nil
+}
+
+// The flags accepted by terraform plan, the value indicates if the flag expects a value
+var planFlags = map[string]bool{
+	"-compact-warnings": false,
+	"-destroy":          false,
+	"-input":            false,
+	"-lock":             false,
+	"-lock-timeout":     true,
+	"-module-depth":     true,
+	"-no-color":         false,
+	"-parallelism":      true,
+	"-refresh":          false,
+	"-refresh-only":     false,
+	"-replace":          true,
+	"-state":            true,
+	"-target":           true,
+	"-var":              true,
+	"-var-file":         true,
+}
+
+// Returns the arguments of an apply command that are also accepted by terraform plan (i.e. without -auto-approve,
+// -backup or the positional arguments)
+func planArguments(args []string) []string {
+	filtered := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		expectsValue, accepted := planFlags[strings.SplitN(args[i], "=", 2)[0]]
+		if !accepted {
+			continue
+		}
+		filtered = append(filtered, args[i])
+		if expectsValue && !strings.Contains(args[i], "=") && i+1 < len(args) {
+			i++
+			filtered = append(filtered, args[i])
+		}
+	}
+	return filtered
+}
+
+// Returns the arguments without the -var and -var-file arguments
+func removeVarsAndVarFiles(args []string) []string {
+	const varFile = "-var-file="
+	const varArg = "-var"
+
 	filtered := make([]string, 0, len(args))
 	for i := 0; i < len(args); i++ {
 		if strings.HasPrefix(args[i], varFile) {
@@ -180,8 +305,7 @@ func filterVarsAndVarFiles(command string, terragruntOptions *options.Terragrunt
 		}
 		filtered = append(filtered, args[i])
 	}
-
-	return filtered, nil
+	return filtered
 }
 
 func extractVarArgs() []string {
@@ -247,6 +371,22 @@ func parseStringArg(args []string, argName string, defaultValue string) (string,
 	return defaultValue, nil
 }
 
+// Find all occurrences of a string argument (e.g. --foo "VALUE1" --foo "VALUE2") of the given name in the given list
+// of arguments and return their values. If one of them has no value, return an error.
+func parseStringListArg(args []string, argName string) ([]string, error) {
+	givenArg := fmt.Sprintf("--%s", argName)
+	var result []string
+	for i, arg := range args {
+		if arg == givenArg {
+			if (i + 1) >= len(args) {
+				return nil, errors.WithStackTrace(ErrArgMissingValue(argName))
+			}
+			result = append(result, args[i+1])
+		}
+	}
+	return result, nil
+}
+
 // ErrArgMissingValue indicates that there is a missing argument value
 type ErrArgMissingValue string
 
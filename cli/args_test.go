@@ -213,3 +213,31 @@ func TestParseEnvironmentVariables(t *testing.T) {
 		assert.Equal(t, testCase.expectedVariables, mockOptions.Env)
 	}
 }
+
+func TestCompleteTerraformArgsWithSavedPlan(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("test-plan-file")
+	terragruntOptions.NonInteractive = true
+	terragruntOptions.PlanFile = "/plans/module.tfplan"
+
+	// The variables defined by the extra_arguments are not passed with a saved plan
+	extraArgs := removeVarsAndVarFiles([]string{"-lock-timeout=20m", "-var", "region=us-east-1", "-var-file=/config/common.tfvars"})
+	terragruntOptions.TerraformCliArgs = append([]string{"apply"}, append(extraArgs, "-input=false")...)
+
+	// The saved plan is the last argument, after -auto-approve
+	expected := []string{"apply", "-lock-timeout=20m", "-input=false", "-auto-approve", "/plans/module.tfplan"}
+	assert.Equal(t, expected, completeTerraformArgs(terragruntOptions, true))
+
+	terragruntOptions.PlanFile = ""
+	terragruntOptions.TerraformCliArgs = []string{"plan", "-input=false"}
+	assert.Equal(t, []string{"plan", "-input=false"}, completeTerraformArgs(terragruntOptions, false))
+}
+
+func TestPlanArguments(t *testing.T) {
+	t.Parallel()
+
+	args := []string{"-input=false", "-auto-approve", "-lock-timeout=20m", "-parallelism", "5", "-backup", "backup.tfstate", "-var", "a=1", "-var-file=b.tfvars", "-target", "aws_instance.a"}
+	expected := []string{"-input=false", "-lock-timeout=20m", "-parallelism", "5", "-var", "a=1", "-var-file=b.tfvars", "-target", "aws_instance.a"}
+	assert.Equal(t, expected, planArguments(args))
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -36,11 +37,28 @@ const (
 	OptLoggingLevel                     = "terragrunt-logging-level"
 	OptFlushDelay                       = "terragrunt-flush-delay"
 	OptNbWorkers                        = "terragrunt-workers"
+	OptReport                           = "terragrunt-report"
+	OptResume                           = "terragrunt-resume"
+	OptChangedSince                     = "terragrunt-changed-since"
+	OptChangedDependents                = "terragrunt-changed-dependents"
+	OptIncludeDir                       = "terragrunt-include-dir"
+	OptExcludeDir                       = "terragrunt-exclude-dir"
+	OptIncludeDependencies              = "terragrunt-include-dependencies"
+	OptModuleTimeout                    = "terragrunt-module-timeout"
+	OptDeadline                         = "terragrunt-deadline"
+	OptFailFast                         = "terragrunt-fail-fast"
+	OptFailFastInterrupt                = "terragrunt-fail-fast-interrupt"
+	OptConcurrencyLimit                 = "terragrunt-concurrency-limit"
+	OptPlanDir                          = "terragrunt-plan-dir"
+	OptAllowDestroy                     = "terragrunt-allow-destroy"
+	OptInferDependencies                = "terragrunt-infer-dependencies"
+	OptNonStrictConfig                  = "terragrunt-non-strict-config"
+	OptCacheTTL                         = "terragrunt-cache-ttl"
 	OptAWSProfile                       = "profile"
 )
 
-var allTerragruntBooleanOpts = []string{optNonInteractive, optTerragruntSourceUpdate, OptTerragruntIgnoreDependencyErrors}
-var allTerragruntStringOpts = []string{optTerragruntConfig, optTerragruntTFPath, optWorkingDir, optTerragruntSource, OptLoggingLevel, OptAWSProfile, optApprovalHandler, OptFlushDelay, OptNbWorkers}
+var allTerragruntBooleanOpts = []string{optNonInteractive, optTerragruntSourceUpdate, OptTerragruntIgnoreDependencyErrors, OptChangedDependents, OptIncludeDependencies, OptFailFast, OptFailFastInterrupt, OptAllowDestroy, OptInferDependencies, OptNonStrictConfig}
+var allTerragruntStringOpts = []string{optTerragruntConfig, optTerragruntTFPath, optWorkingDir, optTerragruntSource, OptLoggingLevel, OptAWSProfile, optApprovalHandler, OptFlushDelay, OptNbWorkers, OptReport, OptResume, OptChangedSince, OptIncludeDir, OptExcludeDir, OptModuleTimeout, OptDeadline, OptConcurrencyLimit, OptPlanDir, OptCacheTTL}
 
 const multiModuleSuffix = "-all"
 const cmdInit = "init"
@@ -94,12 +112,15 @@ COMMANDS:
    get-doc [options...] [filters...] Print the documentation of all extra_arguments, import_files, pre_hook, post_hook and extra_command.
    get-versions                      Get all versions of underlying tools (including extra_command).
    get-stack [options]               Get the list of stack to execute sorted by dependency order.
+   validate-config                   Validate the configuration files without running terraform and report all the problems found.
+   render-config [options]           Print the resolved configuration with the origin of the extensions and remote_state keys.
 
    -all operations:
    plan-all                          Display the plans of a 'stack' by running 'terragrunt plan' in each subfolder (with a summary at the end).
-   apply-all                         Apply a 'stack' by running 'terragrunt apply' in each subfolder.
+   apply-all                         Apply a 'stack' by running 'terragrunt apply' in each subfolder (or the plans saved by plan-all with --terragrunt-plan-dir).
    output-all                        Display the outputs of a 'stack' by running 'terragrunt output' in each subfolder (no error if a subfolder doesn't have outputs).
    destroy-all                       Destroy a 'stack' by running 'terragrunt destroy' in each subfolder in reverse dependency order.
+   validate-config-all               Validate the configuration files of a 'stack' (including dependencies and cycles) without running terraform.
    *-all                             In fact, the -all could be applied on any terraform or custom commands (that's cool).
 
    terraform commands:
@@ -117,11 +138,29 @@ GLOBAL OPTIONS:
    terragrunt-approval                  Program to use for approval. {val} will be replaced by the current terragrunt output. Ex: approval.py --value {val}
    terragrunt-flush-delay               Maximum delay on -all commands before printing out traces (NOTICE) indicating that the process is still alive (default 60s).
    terragrunt-workers                   Number of concurrent workers (default 10).
+   terragrunt-report                    Write a report of *-all commands to the specified file (JUnit XML if the extension is .xml, JSON otherwise).
+   terragrunt-resume                    Resume a previous *-all command identified by its run id (TERRAGRUNT_RUN_ID), skipping the modules that already succeeded.
+   terragrunt-changed-since             *-all commands only process the modules affected by the files changed since the specified git reference.
+   terragrunt-changed-dependents        With terragrunt-changed-since, also process the modules that depend on the affected modules.
+   terragrunt-include-dir               *-all commands only process the folders matching the specified glob pattern (could be specified multiple times).
+   terragrunt-exclude-dir               *-all commands ignore the folders matching the specified glob pattern (could be specified multiple times).
+   terragrunt-include-dependencies      With terragrunt-include-dir or terragrunt-exclude-dir, also process the dependencies of the selected modules.
+   terragrunt-module-timeout            Maximum duration of each module processed by *-all commands (i.e. 30m), could be overridden by the timeout attribute.
+   terragrunt-deadline                  Maximum duration (i.e. 2h) or end time (RFC3339) of *-all commands, the remaining modules are interrupted or not started.
+   terragrunt-fail-fast                 *-all commands do not start new modules as soon as a module fails (the running modules are allowed to finish).
+   terragrunt-fail-fast-interrupt       Same as terragrunt-fail-fast, but the running modules are also interrupted.
+   terragrunt-concurrency-limit         Maximum number of simultaneous modules of a concurrency group (i.e. account-a=2), could be specified multiple times.
+   terragrunt-allow-destroy             apply-all is allowed to destroy or replace the resources protected by the protect blocks.
+   terragrunt-non-strict-config         Only warn about the unknown attributes and the invalid values in the terragrunt blocks instead of failing.
+   terragrunt-infer-dependencies        Infer the dependencies between modules from their terraform_remote_state data sources (s3 backend).
+   terragrunt-plan-dir                  plan-all saves the plan of each module in the specified folder, apply-all applies the plans saved in that folder.
+   terragrunt-cache-ttl                 Keep the results of the AWS calls made by the interpolation functions (i.e. get_aws_account_id) on disk for the specified duration (i.e. 1h).
    profile                              Specify an AWS profile to use.
 
 ENVIRONMENT VARIABLES:
    The following environment variables could be set to avoid specifying parameters on command line:
-	  TERRAGRUNT_CONFIG, TERRAGRUNT_TFPATH, TERRAGRUNT_SOURCE, TERRAGRUNT_LOGGING_LEVEL, TERRAGRUNT_FLUSH_DELAY, TERRAGRUNT_WORKERS
+	  TERRAGRUNT_CONFIG, TERRAGRUNT_TFPATH, TERRAGRUNT_SOURCE, TERRAGRUNT_LOGGING_LEVEL, TERRAGRUNT_FLUSH_DELAY, TERRAGRUNT_WORKERS, TERRAGRUNT_REPORT,
+	  TERRAGRUNT_INCLUDE_DIR, TERRAGRUNT_EXCLUDE_DIR (multiple patterns separated by the path list separator), TERRAGRUNT_CACHE_TTL
 	  
    TERRAGRUNT_DEBUG  If set, this enable detailed stack trace in case of application crash
    TERRAGRUNT_CACHE  If set, it defines the root folder used to store temporary files
@@ -238,6 +277,12 @@ func runCommand(command string, terragruntOptions *options.TerragruntOptions) (f
 	if command == getStackCommand || strings.HasSuffix(command, multiModuleSuffix) {
 		return runMultiModuleCommand(command, terragruntOptions)
 	}
+	if command == validateConfigCommand {
+		return validateConfig(terragruntOptions)
+	}
+	if command == renderConfigCommand {
+		return renderConfig(terragruntOptions)
+	}
 	return runTerragrunt(terragruntOptions)
 }
 
@@ -267,6 +312,10 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 		return err
 	}
 
+	if err = setDependencyOutputs(terragruntOptions, conf); err != nil {
+		return err
+	}
+
 	sourceURL, hasSourceURL := getTerraformSourceURL(terragruntOptions, conf)
 	if sourceURL == "" {
 		sourceURL = terragruntOptions.WorkingDir
@@ -327,6 +376,10 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 		// There is a corner case when initializing map variables from command line
 		filterVarsAndVarFiles(actualCommand.Command, terragruntOptions, extractVarArgs())
 
+		if terragruntOptions.PlanFile != "" {
+			// Terraform refuses the variables when a saved plan is applied, they are already in the plan
+			extraArgs = removeVarsAndVarFiles(extraArgs)
+		}
 		args = append(args, extraArgs...)
 		if commandLength <= len(terragruntOptions.TerraformCliArgs) {
 			args = append(args, terragruntOptions.TerraformCliArgs[commandLength:]...)
@@ -513,9 +566,19 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 	shell.NewTFCmd(terragruntOptions).Args([]string{"init", "--backend=false"}...).WithRetries(3).Output()
 
 	isApply := actualCommand.Command == "apply" || (actualCommand.Extra != nil && actualCommand.Extra.ActAs == "apply")
-	if terragruntOptions.NonInteractive && isApply && !util.ListContainsElement(terragruntOptions.TerraformCliArgs, "-auto-approve") {
-		terragruntOptions.TerraformCliArgs = append(terragruntOptions.TerraformCliArgs, "-auto-approve")
+	if isApply && terragruntOptions.ValidatePlan != nil && terragruntOptions.PlanFile == "" {
+		planDir, planErr := ioutil.TempDir("", "terragrunt-plan")
+		if planErr != nil {
+			return errors.WithStackTrace(planErr)
+		}
+		defer os.RemoveAll(planDir)
+
+		// The apply is never done if the plan is not valid, even if ignore_error is set
+		if err = planBeforeApply(terragruntOptions, filepath.Join(planDir, "apply.tfplan")); err != nil {
+			return errors.WithStackTrace(err)
+		}
 	}
+	terragruntOptions.TerraformCliArgs = completeTerraformArgs(terragruntOptions, isApply)
 
 	var cmd *shell.CommandContext
 
@@ -550,7 +613,8 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 
 		cmd = shell.NewTFCmd(terragruntOptions).Args(terragruntOptions.TerraformCliArgs...)
 	}
-	if shouldBeApproved, approvalConfig := conf.ApprovalConfig.ShouldBeApproved(actualCommand.Command); shouldBeApproved {
+	// Terraform does not ask for approval when a saved plan is applied
+	if shouldBeApproved, approvalConfig := conf.ApprovalConfig.ShouldBeApproved(actualCommand.Command); shouldBeApproved && terragruntOptions.PlanFile == "" {
 		cmd = cmd.Expect(approvalConfig.ExpectStatements, approvalConfig.CompletedStatements)
 	}
 	err = shell.FilterPlanError(cmd.Run(), actualCommand.Command)
@@ -567,6 +631,34 @@ func runTerragrunt(terragruntOptions *options.TerragruntOptions) (finalStatus er
 	return
 }
 
+// Returns the terraform arguments with the arguments added by Terragrunt. The -auto-approve argument is added to apply
+// in non interactive mode and the saved plan (if any) is always the last argument.
+func completeTerraformArgs(terragruntOptions *options.TerragruntOptions, isApply bool) []string {
+	args := terragruntOptions.TerraformCliArgs
+	if terragruntOptions.NonInteractive && isApply && !util.ListContainsElement(args, "-auto-approve") {
+		args = append(args, "-auto-approve")
+	}
+	if terragruntOptions.PlanFile != "" {
+		args = append(args, terragruntOptions.PlanFile)
+	}
+	return args
+}
+
+// Saves the plan of the changes in the given file and validates it (see options.ValidatePlan). The plan is then applied
+// instead of the changes, the variables are removed from the arguments since they are already in the saved plan.
+func planBeforeApply(terragruntOptions *options.TerragruntOptions, planFile string) error {
+	args := append([]string{"plan", "-out=" + planFile}, planArguments(terragruntOptions.TerraformCliArgs[1:])...)
+	if err := shell.NewTFCmd(terragruntOptions).Args(args...).Run(); err != nil {
+		return err
+	}
+	if err := terragruntOptions.ValidatePlan(terragruntOptions, planFile); err != nil {
+		return err
+	}
+	terragruntOptions.TerraformCliArgs = removeVarsAndVarFiles(terragruntOptions.TerraformCliArgs)
+	terragruntOptions.PlanFile = planFile
+	return nil
+}
+
 // Returns true if the command the user wants to execute is supposed to affect multiple Terraform modules, such as the
 // apply-all or destroy-all command.
 func isMultiModuleCommand(command string) bool {
@@ -578,6 +670,8 @@ func runMultiModuleCommand(command string, terragruntOptions *options.Terragrunt
 	realCommand := strings.TrimSuffix(command, multiModuleSuffix)
 	if command == getStackCommand {
 		return getStack(terragruntOptions)
+	} else if command == validateConfigCommand+multiModuleSuffix {
+		return validateAllConfigs(terragruntOptions)
 	} else if strings.HasPrefix(command, "plan-") {
 		return planAll(realCommand, terragruntOptions)
 	} else if strings.HasPrefix(command, "apply-") {
@@ -666,13 +760,19 @@ func applyAll(command string, terragruntOptions *options.TerragruntOptions) erro
 	}
 
 	prompt := fmt.Sprintf("%s\nAre you sure you want to run 'terragrunt apply' in each folder of the stack described above?", stack)
+	if terragruntOptions.PlanDir != "" {
+		prompt = fmt.Sprintf("%s\nAre you sure you want to apply the plans saved in %s for each folder of the stack described above?", stack, terragruntOptions.PlanDir)
+	}
 	shouldApplyAll, err := shell.PromptUserForYesNo(prompt, terragruntOptions)
 	if err != nil {
 		return err
 	}
 
 	if shouldApplyAll {
-		return stack.RunAll([]string{command, "-input=false"}, terragruntOptions, configstack.NormalOrder)
+		if terragruntOptions.PlanDir != "" {
+			return stack.ApplyPlans(command, terragruntOptions)
+		}
+		return stack.Apply(command, terragruntOptions)
 	}
 
 	return nil
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The outputs of a dependency, they are only retrieved once per run
+type dependencyOutputs struct {
+	once    sync.Once
+	outputs map[string]interface{}
+	err     error
+}
+
+// The outputs of the dependencies indexed by dependency folder, shared by all modules of the run
+var dependencyOutputsCache = struct {
+	sync.Mutex
+	entries map[string]*dependencyOutputs
+}{entries: map[string]*dependencyOutputs{}}
+
+// Retrieve the outputs of the dependencies defined in the configuration and expose them as the terragrunt variable
+// dependency.<name> and as terraform variables (through TF_VAR_ environment variables)
+func setDependencyOutputs(terragruntOptions *options.TerragruntOptions, conf *config.TerragruntConfig) error {
+	command := util.IndexOrDefault(terragruntOptions.TerraformCliArgs, 0, "")
+	if len(conf.DependencyList) == 0 || command == "output" {
+		// The outputs are not required to get the outputs of the module (this also avoids retrieving the outputs of
+		// the dependencies recursively)
+		return nil
+	}
+
+	for _, dependency := range conf.DependencyList {
+		// The path has been made absolute relatively to the file declaring the dependency (see config.ParseConfigFile)
+		path, err := util.CanonicalPath(dependency.Path, "")
+		if err != nil {
+			return err
+		}
+
+		outputs, err := getDependencyOutputs(path, terragruntOptions)
+		if err != nil {
+			return err
+		}
+		if len(outputs) == 0 {
+			if !dependency.MockOutputsAllowed(command) {
+				return errors.WithStackTrace(DependencyOutputsNotFound{dependency.Name, path})
+			}
+			terragruntOptions.Logger.Warningf("Dependency %s has no output, using the mock outputs", dependency.Name)
+			outputs = dependency.MockOutputs
+		}
+
+		terragruntOptions.SetVariable("dependency."+dependency.Name, outputs, options.Default)
+		for key, value := range outputs {
+			if terragruntOptions.Env["TF_VAR_"+dependency.Prefix()+key], err = tfVarValue(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the outputs of the module in the folder, the outputs are retrieved with `terragrunt output -json` only once
+// per run
+func getDependencyOutputs(path string, terragruntOptions *options.TerragruntOptions) (map[string]interface{}, error) {
+	dependencyOutputsCache.Lock()
+	entry := dependencyOutputsCache.entries[path]
+	if entry == nil {
+		entry = &dependencyOutputs{}
+		dependencyOutputsCache.entries[path] = entry
+	}
+	dependencyOutputsCache.Unlock()
+
+	entry.once.Do(func() {
+		entry.outputs, entry.err = fetchDependencyOutputs(path, terragruntOptions)
+	})
+	return entry.outputs, entry.err
+}
+
+// Run `terragrunt output -json` in the folder of the dependency and return the value of its outputs
+func fetchDependencyOutputs(path string, terragruntOptions *options.TerragruntOptions) (map[string]interface{}, error) {
+	terragruntOptions.Logger.Infof("Retrieving the outputs of %s", path)
+
+	var stdout, stderr bytes.Buffer
+	dependencyOptions := terragruntOptions.Clone(config.DefaultConfigPath(path))
+	dependencyOptions.TerraformCliArgs = []string{"output", "-json"}
+	resetModuleState(dependencyOptions)
+	dependencyOptions.Writer = &util.LogCatcher{Writer: &stdout, Logger: terragruntOptions.Logger}
+	dependencyOptions.ErrWriter = &util.LogCatcher{Writer: &stderr, Logger: terragruntOptions.Logger}
+
+	if err := dependencyOptions.RunTerragrunt(dependencyOptions); err != nil {
+		if strings.Contains(stdout.String()+stderr.String(), "no outputs defined") {
+			// Old terraform versions return an error if there is no output
+			return nil, nil
+		}
+		return nil, errors.WithStackTraceAndPrefix(err, "Unable to get the outputs of %s", path)
+	}
+
+	output := stdout.String()
+	start, end := strings.Index(output, "{"), strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return nil, nil
+	}
+
+	var outputs map[string]struct {
+		Value interface{} `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(output[start:end+1]), &outputs); err != nil {
+		return nil, errors.WithStackTraceAndPrefix(err, "Unable to parse the outputs of %s", path)
+	}
+
+	result := make(map[string]interface{}, len(outputs))
+	for key, output := range outputs {
+		result[key] = output.Value
+	}
+	return result, nil
+}
+
+// Removes the state inherited from the current module that must not be used to run the dependency (i.e. the saved plan
+// and the variables). Only the TF_VAR_ environment variables of the process are kept.
+func resetModuleState(terragruntOptions *options.TerragruntOptions) {
+	terragruntOptions.PlanFile = ""
+	terragruntOptions.ValidatePlan = nil
+	terragruntOptions.Variables = make(map[string]options.Variable)
+
+	var environment []string
+	for key := range terragruntOptions.Env {
+		if strings.HasPrefix(key, "TF_VAR_") {
+			delete(terragruntOptions.Env, key)
+		}
+	}
+	for _, variable := range os.Environ() {
+		if strings.HasPrefix(variable, "TF_VAR_") {
+			environment = append(environment, variable)
+		}
+	}
+	parseEnvironmentVariables(terragruntOptions, environment)
+}
+
+// Returns the value as expected by terraform in a TF_VAR_ environment variable (lists and maps are expressed in
+// JSON which is compatible with HCL)
+func tfVarValue(value interface{}) (string, error) {
+	if value, isString := value.(string); isString {
+		return value, nil
+	}
+	content, err := json.Marshal(value)
+	return string(content), errors.WithStackTrace(err)
+}
+
+// DependencyOutputsNotFound is the error returned when a dependency has no output and mock outputs are not allowed
+type DependencyOutputsNotFound struct {
+	Name string
+	Path string
+}
+
+func (err DependencyOutputsNotFound) Error() string {
+	return fmt.Sprintf("Dependency %s (%s) has no output, it must be applied first or mock_outputs must be defined for this command", err.Name, err.Path)
+}
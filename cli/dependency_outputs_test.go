@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDependencyOutputs(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	terragruntOptions := options.NewTerragruntOptionsForTest("/test-dependency-outputs/app/terraform.tfvars")
+	terragruntOptions.TerraformCliArgs = []string{"plan"}
+	terragruntOptions.RunTerragrunt = func(dependencyOptions *options.TerragruntOptions) error {
+		calls++
+		assert.Equal(t, []string{"output", "-json"}, dependencyOptions.TerraformCliArgs)
+		if dependencyOptions.WorkingDir == "/test-dependency-outputs/vpc" {
+			fmt.Fprintln(dependencyOptions.Writer, `{"vpc_id": {"sensitive": false, "type": "string", "value": "vpc-1"}, "subnets": {"value": ["a", "b"]}}`)
+		} else {
+			fmt.Fprintln(dependencyOptions.Writer, "{}")
+		}
+		return nil
+	}
+
+	conf := &config.TerragruntConfig{DependencyList: config.DependencyList{
+		{Name: "vpc", Path: "/test-dependency-outputs/vpc"},
+		{Name: "db", Path: "/test-dependency-outputs/db", MockOutputs: map[string]interface{}{"endpoint": "mock"}},
+	}}
+	assert.Nil(t, setDependencyOutputs(terragruntOptions, conf))
+
+	assert.Equal(t, "vpc-1", terragruntOptions.Env["TF_VAR_vpc_vpc_id"])
+	assert.Equal(t, `["a","b"]`, terragruntOptions.Env["TF_VAR_vpc_subnets"])
+	assert.Equal(t, "mock", terragruntOptions.Env["TF_VAR_db_endpoint"])
+	assert.Equal(t, "vpc-1", config.SubstituteVars("${var.dependency.vpc.vpc_id}", terragruntOptions))
+
+	// The outputs are cached
+	assert.Nil(t, setDependencyOutputs(terragruntOptions, conf))
+	assert.Equal(t, 2, calls)
+
+	// The mock outputs are not allowed for apply
+	terragruntOptions.TerraformCliArgs = []string{"apply"}
+	err := setDependencyOutputs(terragruntOptions, conf)
+	assert.Equal(t, DependencyOutputsNotFound{"db", "/test-dependency-outputs/db"}, errors.Unwrap(err))
+}
+
+func TestDependencyOutputsWithCleanState(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("/test-dependency-clean-state/app/terraform.tfvars")
+	terragruntOptions.TerraformCliArgs = []string{"apply"}
+	terragruntOptions.PlanFile = "/plans/app.tfplan"
+	terragruntOptions.ValidatePlan = func(*options.TerragruntOptions, string) error { return nil }
+	terragruntOptions.Env["TF_VAR_db_endpoint"] = "db-1"
+	terragruntOptions.SetVariable("region", "us-east-1", options.Default)
+	terragruntOptions.RunTerragrunt = func(dependencyOptions *options.TerragruntOptions) error {
+		assert.Equal(t, []string{"output", "-json"}, completeTerraformArgs(dependencyOptions, false))
+		assert.Nil(t, dependencyOptions.ValidatePlan)
+		assert.NotContains(t, dependencyOptions.Env, "TF_VAR_db_endpoint")
+		assert.NotContains(t, dependencyOptions.Variables, "region")
+		fmt.Fprintln(dependencyOptions.Writer, `{"vpc_id": {"value": "vpc-1"}}`)
+		return nil
+	}
+
+	conf := &config.TerragruntConfig{DependencyList: config.DependencyList{{Name: "vpc", Path: "/test-dependency-clean-state/vpc"}}}
+	assert.Nil(t, setDependencyOutputs(terragruntOptions, conf))
+	assert.Equal(t, "vpc-1", terragruntOptions.Env["TF_VAR_vpc_vpc_id"])
+
+	// The state of the current module is not modified
+	assert.Equal(t, "/plans/app.tfplan", terragruntOptions.PlanFile)
+	assert.Equal(t, "db-1", terragruntOptions.Env["TF_VAR_db_endpoint"])
+}
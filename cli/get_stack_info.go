@@ -2,6 +2,7 @@ package cli
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -24,7 +25,7 @@ func getStack(terragruntOptions *options.TerragruntOptions) (err error) {
 	)
 
 	run := app.Flag("run", "Run the full stack to get the result instead of just analysing the dependencies").Short('r').Bool()
-	output := app.Flag("output", "Specify format of the output (hcl, json, yaml)").Short('o').Enum("h", "hcl", "H", "HCL", "j", "json", "J", "JSON", "y", "yml", "yaml", "Y", "YML", "YAML")
+	output := app.Flag("output", "Specify format of the output (hcl, json, yaml, dot, mermaid)").Short('o').Enum("h", "hcl", "H", "HCL", "j", "json", "J", "JSON", "y", "yml", "yaml", "Y", "YML", "YAML", "d", "dot", "D", "DOT", "m", "mermaid", "M", "MERMAID")
 	app.Flag("absolute", "Output absolute path (--abs)").Short('a').BoolVar(&absolute)
 	app.Flag("abs", "").Hidden().BoolVar(&absolute)
 	app.HelpFlag.Short('h')
@@ -32,6 +33,14 @@ func getStack(terragruntOptions *options.TerragruntOptions) (err error) {
 		return
 	}
 
+	switch strings.ToLower(*output) {
+	case "d", "dot", "m", "mermaid":
+		if *run {
+			return fmt.Errorf("The --run option cannot be used with the %s output format", *output)
+		}
+		return getStackGraph(terragruntOptions, strings.ToLower(*output), absolute)
+	}
+
 	if *run {
 		if modules, err = getStackThroughExecution(terragruntOptions); err != nil {
 			return
@@ -74,6 +83,25 @@ func getStack(terragruntOptions *options.TerragruntOptions) (err error) {
 	return nil
 }
 
+// Print the dependency graph of the stack in dot or mermaid format. A dependency cycle is highlighted in the graph
+// instead of being reported as an error.
+func getStackGraph(terragruntOptions *options.TerragruntOptions, format string, absolute bool) error {
+	stack, cycle, err := configstack.FindStackGraphInSubfolders(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	if cycle != nil {
+		terragruntOptions.Logger.Warning(cycle.Error())
+	}
+
+	if strings.HasPrefix(format, "d") {
+		terragruntOptions.Println(stack.DotGraph(cycle, absolute))
+	} else {
+		terragruntOptions.Println(stack.MermaidGraph(cycle, absolute))
+	}
+	return nil
+}
+
 // Get a list of terraform modules sorted by dependency order (but through real execution of the stack modules)
 // Should give the same result as getStack
 func getStackThroughExecution(terragruntOptions *options.TerragruntOptions) (modules configstack.SimpleTerraformModules, err error) {
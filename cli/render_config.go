@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/coveo/gotemplate/hcl"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const renderConfigCommand = "render-config"
+
+// Print the configuration of the current folder as it is resolved after the includes, the bootstrap files and the
+// templates have been processed. The extensions and the remote_state keys are annotated with their origin and the
+// secrets are redacted.
+func renderConfig(terragruntOptions *options.TerragruntOptions) (err error) {
+	app := kingpin.New("terragrunt render-config", "Print the resolved configuration")
+	output := app.Flag("output", "Specify format of the output (hcl, json, yaml)").Short('o').Default("hcl").Enum("h", "hcl", "H", "HCL", "j", "json", "J", "JSON", "y", "yml", "yaml", "Y", "YML", "YAML")
+	app.HelpFlag.Short('h')
+	if _, err = app.Parse(terragruntOptions.TerraformCliArgs[1:]); err != nil {
+		return
+	}
+
+	conf, err := config.ReadTerragruntConfig(terragruntOptions)
+	if err != nil {
+		return
+	}
+
+	rendered := conf.Render(terragruntOptions.WorkingDir)
+	var result []byte
+	switch strings.ToLower(*output) {
+	case "j", "json":
+		result, err = json.MarshalIndent(rendered, "", "  ")
+	case "y", "yml", "yaml":
+		result, err = yaml.Marshal(rendered)
+	default:
+		result, err = hcl.MarshalIndent(rendered, "", "  ")
+	}
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	// The values of the secrets and of the secure parameters are redacted as in the logs
+	terragruntOptions.Println(terragruntOptions.Cache.RedactSecrets(string(result)))
+	return nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+const validateConfigCommand = "validate-config"
+
+// Validate the configuration of the current folder without running terraform and report all the problems found
+func validateConfig(terragruntOptions *options.TerragruntOptions) error {
+	_, problems := config.ValidateConfigFile(terragruntOptions)
+	return reportValidationProblems(terragruntOptions, problems, 1)
+}
+
+// Validate the configuration of all the modules in the subfolders without running terraform and report all the
+// problems found (including the unknown dependencies and the dependency cycles)
+func validateAllConfigs(terragruntOptions *options.TerragruntOptions) error {
+	terragruntConfigFiles, err := config.FindConfigFilesInPath(terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	var problems config.ValidationProblems
+	reported := map[string]bool{}
+	for _, terragruntConfigFile := range terragruntConfigFiles {
+		_, fileProblems := config.ValidateConfigFile(terragruntOptions.Clone(terragruntConfigFile))
+		for _, problem := range fileProblems {
+			// The problems of the files included by several modules are only reported once
+			if !reported[problem.String()] {
+				reported[problem.String()] = true
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		// The stack could only be resolved if the configuration of all modules is valid
+		_, cycle, err := configstack.FindStackGraphInSubfolders(terragruntOptions)
+		if err != nil {
+			problems = append(problems, config.ValidationProblem{Message: errors.Unwrap(err).Error()})
+		} else if cycle != nil {
+			problems = append(problems, config.ValidationProblem{Message: cycle.Error()})
+		}
+	}
+
+	return reportValidationProblems(terragruntOptions, problems, len(terragruntConfigFiles))
+}
+
+func reportValidationProblems(terragruntOptions *options.TerragruntOptions, problems config.ValidationProblems, nbFiles int) error {
+	if len(problems) > 0 {
+		return errors.WithStackTrace(problems)
+	}
+	terragruntOptions.Logger.Noticef("%d configuration(s) validated, no problem found", nbFiles)
+	return nil
+}
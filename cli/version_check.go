@@ -23,6 +23,7 @@ func CheckTerraformVersion(constraint string, terragruntOptions *options.Terragr
 	}
 
 	terraformVersion = currentVersion.String()
+	terragruntOptions.TerraformVersion = currentVersion
 	return checkTerraformVersionMeetsConstraint(currentVersion, constraint)
 }
 
@@ -6,6 +6,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/coveo/gotemplate/collections"
 	"github.com/coveo/gotemplate/hcl"
@@ -36,7 +37,14 @@ type TerragruntConfig struct {
 	Terraform      *TerraformConfig    `hcl:"terraform"`
 	RemoteState    *remote.RemoteState `hcl:"remote_state"`
 	Dependencies   *ModuleDependencies `hcl:"dependencies"`
+	DependencyList DependencyList      `hcl:"dependency"`
 	Uniqueness     *string             `hcl:"uniqueness_criteria"`
+	Timeout        *string             `hcl:"timeout"`
+	Priority       *int                `hcl:"priority"`
+	Weight         *int                `hcl:"weight"`
+	Group          *string             `hcl:"concurrency_group"`
+	GroupLimits    map[string]int      `hcl:"concurrency_limits"`
+	Protect        ProtectList         `hcl:"protect"`
 	AssumeRole     interface{}         `hcl:"assume_role"`
 	PreHooks       HookList            `hcl:"pre_hook"`
 	PostHooks      HookList            `hcl:"post_hook"`
@@ -45,12 +53,53 @@ type TerragruntConfig struct {
 	ApprovalConfig ApprovalConfigList  `hcl:"approval_config"`
 
 	options *options.TerragruntOptions
+	files   []string                 // The list of files (the file itself, included and bootstrap files) used to build the config
+	origins map[string]*ConfigOrigin // The origin of the extensions and the remote_state keys (see Render)
 }
 
 func (conf TerragruntConfig) String() string {
 	return collections.PrettyPrintStruct(conf)
 }
 
+// Files returns the list of configuration files that have been read to build this configuration (the configuration
+// file itself followed by all its included and bootstrap files)
+func (conf TerragruntConfig) Files() []string {
+	return conf.files
+}
+
+// ImportFilesSources returns the local folders used as source by the import_files of this configuration (remote
+// sources are ignored)
+func (conf TerragruntConfig) ImportFilesSources() (result []string) {
+	for _, item := range conf.ImportFiles {
+		if item.Source == "" || item._config == nil {
+			continue
+		}
+		source := item.Source
+		if item._config.options != nil {
+			source = SubstituteVars(source, item._config.options)
+		}
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(filepath.Dir(item._config.Path), source)
+		}
+		if stat, err := os.Stat(source); err == nil && stat.IsDir() {
+			result = append(result, util.CleanPath(source))
+		}
+	}
+	return
+}
+
+// GetTimeout returns the maximum duration of the module defined by the timeout attribute (0 if it is not defined)
+func (conf TerragruntConfig) GetTimeout() (time.Duration, error) {
+	if conf.Timeout == nil || *conf.Timeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(*conf.Timeout)
+	if err != nil || timeout < 0 {
+		return 0, errors.WithStackTrace(InvalidTimeout(*conf.Timeout))
+	}
+	return timeout, nil
+}
+
 // ExtraArguments processes the extra_arguments defined in the terraform section of the config file
 func (conf TerragruntConfig) ExtraArguments(source string) ([]string, error) {
 	return conf.Terraform.ExtraArgs.Filter(source)
@@ -70,8 +119,9 @@ func (conf TerragruntConfig) globFiles(pattern string, folders ...string) (resul
 // TerragruntConfigFile represents the configuration supported in a Terragrunt configuration file (i.e. terraform.tfvars or .terragrunt)
 type TerragruntConfigFile struct {
 	TerragruntConfig `hcl:",squash"`
-	Include          *IncludeConfig
-	Lock             *LockConfig
+	Include          IncludeList         `hcl:"include"`
+	Lock             *LockConfig         `hcl:"lock"`
+	ImportVariables  ImportVariablesList `hcl:"import_variables"`
 	Path             string
 }
 
@@ -89,6 +139,14 @@ func (tcf *TerragruntConfigFile) convertToTerragruntConfig(terragruntOptions *op
 			terragruntOptions.TerragruntConfigPath)
 	}
 
+	if err = tcf.Protect.Validate(); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	if err = tcf.DependencyList.addToModuleDependencies(&tcf.TerragruntConfig); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
 	if tcf.RemoteState != nil {
 		tcf.RemoteState.FillDefaults()
 		if err = tcf.RemoteState.Validate(); err != nil {
@@ -110,11 +168,19 @@ func (tcf *TerragruntConfigFile) convertToTerragruntConfig(terragruntOptions *op
 		}
 		tcf.AssumeRole = roles
 	default:
-		terragruntOptions.Logger.Errorf("Invalid configuration for assume_role, must be either a string or a list of strings: %[1]v (%[1]T)", role)
+		if terragruntOptions.StrictConfig && !isOldTerragruntConfig(tcf.Path) {
+			return nil, errors.WithStackTrace(InvalidAssumeRole{role})
+		}
+		terragruntOptions.Logger.Error(InvalidAssumeRole{role})
+	}
+
+	if err = tcf.ImportVariables.Import(terragruntOptions); err != nil {
+		return
 	}
 
 	// Make the context available to sub-objects
 	tcf.options = terragruntOptions
+	tcf.initOrigins(tcf.Path)
 
 	if tcf.Terraform != nil {
 		tcf.Terraform.ExtraArgs.init(tcf)
@@ -142,12 +208,19 @@ type tfvarsFileWithTerragruntConfig struct {
 // IncludeConfig represents the configuration settings for a parent Terragrunt configuration file that you can
 // "include" in a child Terragrunt configuration file
 type IncludeConfig struct {
-	Source       string `hcl:"source"`
-	Path         string `hcl:"path"`
-	isIncludedBy *IncludeConfig
-	isBootstrap  bool
+	Name            string            `hcl:",key"` // The optional name of the include (required if there are several includes)
+	Source          string            `hcl:"source"`
+	Path            string            `hcl:"path"`
+	MergeStrategy   string            `hcl:"merge_strategy"`   // The strategy used to merge all the sections (see config_merge.go)
+	MergeStrategies map[string]string `hcl:"merge_strategies"` // The strategy used to merge specific sections
+	isIncludedBy    *IncludeConfig
+	isBootstrap     bool
+	namedIncludes   map[string]*IncludeConfig // The named includes of the file (see path_relative_to_include)
 }
 
+// IncludeList represents the list of include blocks of a configuration file
+type IncludeList []IncludeConfig
+
 func (include IncludeConfig) String() string {
 	var includeBy string
 	if include.isIncludedBy != nil {
@@ -211,6 +284,10 @@ func FindConfigFilesInPath(terragruntOptions *options.TerragruntOptions) ([]stri
 				// the folder
 				return nil
 			}
+			if excluded, err := isExcludedDir(path, terragruntOptions); err != nil || excluded {
+				// The folder is excluded by --terragrunt-include-dir or --terragrunt-exclude-dir
+				return err
+			}
 			configPath := DefaultConfigPath(path)
 			isTerragruntConfig, err := IsTerragruntConfigFile(configPath)
 			if err != nil {
@@ -227,6 +304,56 @@ func FindConfigFilesInPath(terragruntOptions *options.TerragruntOptions) ([]stri
 	return configFiles, err
 }
 
+// Returns true if the folder should not be processed according to the include (--terragrunt-include-dir) and exclude
+// (--terragrunt-exclude-dir) glob patterns. A pattern matching a folder also applies to all its sub folders.
+func isExcludedDir(path string, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	if len(terragruntOptions.IncludeDirs) > 0 {
+		included, err := matchDirGlobs(path, terragruntOptions.IncludeDirs, terragruntOptions.WorkingDir)
+		if err != nil || !included {
+			return true, err
+		}
+	}
+	return matchDirGlobs(path, terragruntOptions.ExcludeDirs, terragruntOptions.WorkingDir)
+}
+
+// Returns true if the folder or one of its parents (up to the root path) matches one of the glob patterns. Relative
+// patterns are matched against the path relative to the root path while absolute patterns are matched against the
+// absolute path.
+func matchDirGlobs(path string, patterns []string, rootPath string) (bool, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+	absRoot, err := filepath.Abs(rootPath)
+	if err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+
+	for folder := absPath; ; folder = filepath.Dir(folder) {
+		relPath, err := filepath.Rel(absRoot, folder)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			break
+		}
+		for _, pattern := range patterns {
+			target := relPath
+			if filepath.IsAbs(pattern) {
+				target = folder
+			}
+			match, err := filepath.Match(filepath.Clean(pattern), target)
+			if err != nil {
+				return false, errors.WithStackTrace(InvalidDirGlob{pattern, err})
+			}
+			if match {
+				return true, nil
+			}
+		}
+		if relPath == "." {
+			break
+		}
+	}
+	return false, nil
+}
+
 // IsTerragruntConfigFile returns true if the given path corresponds to file that could be a Terragrunt config file.
 // A file could be a Terragrunt config file if:
 //   1. The file exists
@@ -365,10 +492,19 @@ func ParseConfigFile(terragruntOptions *options.TerragruntOptions, include Inclu
 
 	terragruntOptions.TerragruntRawConfig, _ = collections.TryAsDictionary(terragrunt)
 
+	if err = checkConfigSchema(configString, include, terragruntOptions); err != nil {
+		return
+	}
+
 	if config, err = parseConfigString(configString, terragruntOptions, include); err != nil {
 		return
 	}
 
+	// The current file is the first one of the list of files used to build the configuration
+	if sourcePath, err := util.CanonicalPath(source, ""); err == nil {
+		config.files = append([]string{sourcePath}, config.files...)
+	}
+
 	if config.Dependencies != nil {
 		// We should convert all dependencies to absolute path
 		folder := filepath.Dir(source)
@@ -378,6 +514,13 @@ func ParseConfigFile(terragruntOptions *options.TerragruntOptions, include Inclu
 				config.Dependencies.Paths[i] = dep
 			}
 		}
+
+		// The path of the dependency blocks is also made absolute to retrieve the outputs of the same modules
+		for i, dependency := range config.DependencyList {
+			if !filepath.IsAbs(dependency.Path) {
+				config.DependencyList[i].Path, err = filepath.Abs(filepath.Join(folder, dependency.Path))
+			}
+		}
 	}
 
 	if include.isIncludedBy == nil {
@@ -392,6 +535,11 @@ var hookWarningGiven, lockWarningGiven bool
 
 // Parse the Terragrunt config contained in the given string.
 func parseConfigString(configString string, terragruntOptions *options.TerragruntOptions, include IncludeConfig) (config *TerragruntConfig, err error) {
+	// The named includes must be known before resolving the interpolations since they could be targeted by functions
+	if include.namedIncludes, err = findNamedIncludes(configString, include, terragruntOptions); err != nil {
+		return
+	}
+
 	configString, err = ResolveTerragruntConfigString(configString, include, terragruntOptions)
 	if err != nil {
 		return
@@ -428,21 +576,21 @@ func parseConfigString(configString string, terragruntOptions *options.Terragrun
 	if err != nil {
 		return
 	}
-	terragruntOptions.Logger.Infof("Loaded configuration\n%v", color.GreenString(fmt.Sprint(terragruntConfigFile)))
+	terragruntOptions.Logger.Infof("Loaded configuration\n%v", color.GreenString(terragruntOptions.Cache.RedactSecrets(fmt.Sprint(terragruntConfigFile))))
 
 	if !path.IsAbs(include.Path) {
 		include.Path, _ = filepath.Abs(include.Path)
 	}
 
-	if terragruntConfigFile.Include == nil {
+	if len(terragruntConfigFile.Include) == 0 {
 		if include.isBootstrap {
 			// This is already a bootstrap file, so we stop the inclusion here
 			return
 		}
-		terragruntConfigFile.Include = &(IncludeConfig{
+		bootstrap := &IncludeConfig{
 			isBootstrap:  true,
 			isIncludedBy: &include,
-		})
+		}
 		// We check if we should merge bootstrap files defined by environment variable TERRAGRUNT_BOOT_CONFIGS
 		paths := strings.Split(os.Getenv(options.EnvBootConfigs), string(os.PathListSeparator))
 		for _, bootstrapFile := range collections.AsList(paths).Reverse().Strings() {
@@ -450,28 +598,38 @@ func parseConfigString(configString string, terragruntOptions *options.Terragrun
 			if bootstrapFile != "" {
 				stat, _ := os.Stat(bootstrapFile)
 				if stat == nil || stat.IsDir() {
-					terragruntConfigFile.Include.Source = bootstrapFile
+					bootstrap.Source = bootstrapFile
 				} else {
-					terragruntConfigFile.Include.Source = path.Dir(bootstrapFile)
-					terragruntConfigFile.Include.Path = path.Base(bootstrapFile)
+					bootstrap.Source = path.Dir(bootstrapFile)
+					bootstrap.Path = path.Base(bootstrapFile)
 				}
 				var bootConfig *TerragruntConfig
-				if bootConfig, err = parseIncludedConfig(terragruntConfigFile.Include, terragruntOptions); err != nil {
+				if bootConfig, err = parseIncludedConfig(bootstrap, terragruntOptions); err != nil {
 					return
 				}
-				config.mergeIncludedConfig(*bootConfig, terragruntOptions)
+				config.mergeIncludedConfig(*bootConfig, *bootstrap, terragruntOptions)
 			}
 		}
 		return
 	}
 
-	terragruntConfigFile.Include.isIncludedBy = &include
-	includedConfig, err := parseIncludedConfig(terragruntConfigFile.Include, terragruntOptions)
-	if err != nil {
+	if err = terragruntConfigFile.Include.validate(); err != nil {
+		err = errors.WithStackTrace(err)
 		return
 	}
 
-	config.mergeIncludedConfig(*includedConfig, terragruntOptions)
+	// The includes are evaluated in order, but they are merged in reverse order since the elements already defined
+	// have precedence. So, a include has precedence over the previous ones.
+	includedConfigs := make([]*TerragruntConfig, len(terragruntConfigFile.Include))
+	for i := range terragruntConfigFile.Include {
+		terragruntConfigFile.Include[i].isIncludedBy = &include
+		if includedConfigs[i], err = parseIncludedConfig(&terragruntConfigFile.Include[i], terragruntOptions); err != nil {
+			return
+		}
+	}
+	for i := len(includedConfigs) - 1; i >= 0; i-- {
+		config.mergeIncludedConfig(*includedConfigs[i], terragruntConfigFile.Include[i], terragruntOptions)
+	}
 	return
 }
 
@@ -497,20 +655,29 @@ func parseConfigStringAsTerragruntConfigFile(configString string, configPath str
 }
 
 // Merge an included config into the current config. Some elements specified in both config will be merged while
-// others will be overridded only if they are not already specified in the original config.
-func (conf *TerragruntConfig) mergeIncludedConfig(includedConfig TerragruntConfig, terragruntOptions *options.TerragruntOptions) {
-	if includedConfig.Description != "" {
+// others will be overridded only if they are not already specified in the original config. The merge strategies of
+// the include could alter this behavior for specific sections (see config_merge.go).
+func (conf *TerragruntConfig) mergeIncludedConfig(includedConfig TerragruntConfig, include IncludeConfig, terragruntOptions *options.TerragruntOptions) {
+	conf.mergeOrigins(includedConfig, include)
+	merge := func(section string) bool { return include.mergeStrategy(section) != ReplaceMerge }
+
+	if merge("description") && includedConfig.Description != "" {
 		if conf.Description != "" {
 			conf.Description += "\n"
 		}
 		conf.Description += includedConfig.Description
 	}
 
-	if conf.RemoteState == nil {
-		conf.RemoteState = includedConfig.RemoteState
+	switch include.mergeStrategy("remote_state") {
+	case DeepMerge:
+		conf.RemoteState = deepMergeRemoteState(conf.RemoteState, includedConfig.RemoteState)
+	case ShallowMerge:
+		if conf.RemoteState == nil {
+			conf.RemoteState = includedConfig.RemoteState
+		}
 	}
 
-	if includedConfig.Terraform != nil {
+	if merge("terraform") && includedConfig.Terraform != nil {
 		if conf.Terraform == nil {
 			conf.Terraform = includedConfig.Terraform
 		} else {
@@ -522,26 +689,75 @@ func (conf *TerragruntConfig) mergeIncludedConfig(includedConfig TerragruntConfi
 		}
 	}
 
-	if conf.Dependencies == nil {
+	if !merge("dependencies") {
+		// The dependencies are not merged
+	} else if conf.Dependencies == nil {
 		conf.Dependencies = includedConfig.Dependencies
 	} else if includedConfig.Dependencies != nil {
 		conf.Dependencies.Paths = append(conf.Dependencies.Paths, includedConfig.Dependencies.Paths...)
 	}
 
+	if merge("dependency") {
+		conf.DependencyList.merge(includedConfig.DependencyList)
+	}
+
 	if conf.Uniqueness == nil {
 		conf.Uniqueness = includedConfig.Uniqueness
 	}
 
+	if conf.Timeout == nil {
+		conf.Timeout = includedConfig.Timeout
+	}
+
+	if conf.Priority == nil {
+		conf.Priority = includedConfig.Priority
+	}
+
+	if conf.Weight == nil {
+		conf.Weight = includedConfig.Weight
+	}
+
+	if conf.Group == nil {
+		conf.Group = includedConfig.Group
+	}
+
+	if merge("concurrency_limits") {
+		for group, limit := range includedConfig.GroupLimits {
+			if _, exist := conf.GroupLimits[group]; !exist {
+				if conf.GroupLimits == nil {
+					conf.GroupLimits = make(map[string]int, len(includedConfig.GroupLimits))
+				}
+				conf.GroupLimits[group] = limit
+			}
+		}
+	}
+
 	if conf.AssumeRole == nil {
 		conf.AssumeRole = includedConfig.AssumeRole
 	}
 
-	conf.RunConditions.Merge(includedConfig.RunConditions)
-	conf.ImportFiles.Merge(includedConfig.ImportFiles)
-	conf.ExtraCommands.Merge(includedConfig.ExtraCommands)
-	conf.ApprovalConfig.Merge(includedConfig.ApprovalConfig)
-	conf.PreHooks.MergePrepend(includedConfig.PreHooks)
-	conf.PostHooks.MergeAppend(includedConfig.PostHooks)
+	if merge("protect") {
+		conf.Protect = append(conf.Protect, includedConfig.Protect...)
+	}
+	if merge("run_conditions") {
+		conf.RunConditions.Merge(includedConfig.RunConditions)
+	}
+	if merge("import_files") {
+		conf.ImportFiles.Merge(includedConfig.ImportFiles)
+	}
+	if merge("extra_command") {
+		conf.ExtraCommands.Merge(includedConfig.ExtraCommands)
+	}
+	if merge("approval_config") {
+		conf.ApprovalConfig.Merge(includedConfig.ApprovalConfig)
+	}
+	if merge("pre_hook") {
+		conf.PreHooks.MergePrepend(includedConfig.PreHooks)
+	}
+	if merge("post_hook") {
+		conf.PostHooks.MergeAppend(includedConfig.PostHooks)
+	}
+	conf.files = append(conf.files, includedConfig.files...)
 }
 
 // Parse the config of the given include, if one is specified
@@ -579,3 +795,29 @@ type CouldNotResolveTerragruntConfigInFile string
 func (err CouldNotResolveTerragruntConfigInFile) Error() string {
 	return fmt.Sprintf("Could not find Terragrunt configuration settings in %s", string(err))
 }
+
+// InvalidDirGlob is the error returned when an include or exclude folder pattern is not a valid glob pattern
+type InvalidDirGlob struct {
+	Pattern string
+	Err     error
+}
+
+func (err InvalidDirGlob) Error() string {
+	return fmt.Sprintf("Invalid folder pattern %s: %v", err.Pattern, err.Err)
+}
+
+// InvalidAssumeRole is the error returned in strict mode when assume_role is neither a string nor a list of strings
+type InvalidAssumeRole struct {
+	Value interface{}
+}
+
+func (err InvalidAssumeRole) Error() string {
+	return fmt.Sprintf("Invalid configuration for assume_role, must be either a string or a list of strings: %[1]v (%[1]T)", err.Value)
+}
+
+// InvalidTimeout is the error returned when the timeout attribute is not a valid duration
+type InvalidTimeout string
+
+func (timeout InvalidTimeout) Error() string {
+	return fmt.Sprintf("Invalid timeout %q, it must be expressed as a positive duration with unit (i.e. 30m)", string(timeout))
+}
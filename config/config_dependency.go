@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// DependencyConfig defines a module whose outputs are used by the current module. The outputs are exposed as the
+// terragrunt variable dependency.<name> and as terraform variables (prefixed by <name>_ by default).
+type DependencyConfig struct {
+	Name                       string                 `hcl:",key"`
+	Path                       string                 `hcl:"path"`
+	VariablesPrefix            *string                `hcl:"variables_prefix"`
+	MockOutputs                map[string]interface{} `hcl:"mock_outputs"`
+	MockOutputsAllowedCommands []string               `hcl:"mock_outputs_allowed_terraform_commands"`
+}
+
+// DependencyList represents the list of dependency blocks
+type DependencyList []DependencyConfig
+
+// The commands that are allowed to use the mock outputs if mock_outputs_allowed_terraform_commands is not specified
+var defaultMockOutputsAllowedCommands = []string{"plan", "validate"}
+
+func (dependency DependencyConfig) String() string {
+	return fmt.Sprintf("dependency %s (%s)", dependency.Name, dependency.Path)
+}
+
+// Prefix returns the prefix added to the outputs of the dependency to name the terraform variables
+func (dependency DependencyConfig) Prefix() string {
+	if dependency.VariablesPrefix == nil {
+		return dependency.Name + "_"
+	}
+	return *dependency.VariablesPrefix
+}
+
+// MockOutputsAllowed returns true if the mock outputs could be used for the terraform command when the dependency
+// has no output (i.e. it has not been applied yet)
+func (dependency DependencyConfig) MockOutputsAllowed(command string) bool {
+	if dependency.MockOutputs == nil {
+		return false
+	}
+	allowed := dependency.MockOutputsAllowedCommands
+	if allowed == nil {
+		allowed = defaultMockOutputsAllowedCommands
+	}
+	return util.ListContainsElement(allowed, command)
+}
+
+// Add the dependencies that are not already defined (the dependencies of the current config have precedence)
+func (list *DependencyList) merge(included DependencyList) {
+	for _, dependency := range included {
+		if list.find(dependency.Name) == nil {
+			*list = append(*list, dependency)
+		}
+	}
+}
+
+func (list DependencyList) find(name string) *DependencyConfig {
+	for i := range list {
+		if list[i].Name == name {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+// Add the path of the dependencies to the module dependencies to ensure that they are processed first by the *-all
+// commands
+func (list DependencyList) addToModuleDependencies(conf *TerragruntConfig) error {
+	for _, dependency := range list {
+		if dependency.Path == "" {
+			return DependencyMissingPath(dependency.Name)
+		}
+		if conf.Dependencies == nil {
+			conf.Dependencies = &ModuleDependencies{}
+		}
+		if !util.ListContainsElement(conf.Dependencies.Paths, dependency.Path) {
+			conf.Dependencies.Paths = append(conf.Dependencies.Paths, dependency.Path)
+		}
+	}
+	return nil
+}
+
+// DependencyMissingPath is the error returned when a dependency block does not define the path of the dependency
+type DependencyMissingPath string
+
+func (err DependencyMissingPath) Error() string {
+	return fmt.Sprintf("The path of dependency %s is not defined", string(err))
+}
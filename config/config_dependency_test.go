@@ -0,0 +1,88 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTerragruntConfigDependency(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terragrunt = {
+  dependencies {
+    paths = ["../iam"]
+  }
+
+  dependency "vpc" {
+    path = "../vpc"
+
+    mock_outputs = {
+      vpc_id = "vpc-mock"
+    }
+  }
+
+  dependency "db" {
+    path             = "../db"
+    variables_prefix = ""
+  }
+}
+`
+
+	terragruntConfig, err := parseConfigString(config, mockOptions, mockDefaultInclude)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, []string{"../iam", "../vpc", "../db"}, terragruntConfig.Dependencies.Paths)
+	if assert.Len(t, terragruntConfig.DependencyList, 2) {
+		vpc, db := terragruntConfig.DependencyList[0], terragruntConfig.DependencyList[1]
+		assert.Equal(t, "vpc", vpc.Name)
+		assert.Equal(t, map[string]interface{}{"vpc_id": "vpc-mock"}, vpc.MockOutputs)
+		assert.Equal(t, "vpc_", vpc.Prefix())
+		assert.True(t, vpc.MockOutputsAllowed("plan"))
+		assert.False(t, vpc.MockOutputsAllowed("apply"))
+		assert.Equal(t, "", db.Prefix())
+		assert.False(t, db.MockOutputsAllowed("plan"))
+	}
+}
+
+func TestParseTerragruntConfigDependencyMissingPath(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terragrunt = {
+  dependency "vpc" {}
+}
+`
+
+	_, err := parseConfigString(config, mockOptions, mockDefaultInclude)
+	assert.Equal(t, DependencyMissingPath("vpc"), errors.Unwrap(err))
+}
+
+func TestParseTerragruntConfigDependencyInParent(t *testing.T) {
+	t.Parallel()
+
+	// The path of a dependency defined in an included file is relative to that file
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-dependency/app/" + DefaultTerragruntConfigPath)
+	terragruntConfig, err := ParseConfigFile(terragruntOptions, IncludeConfig{Path: terragruntOptions.TerragruntConfigPath})
+	if !assert.Nil(t, err) || !assert.Len(t, terragruntConfig.DependencyList, 1) {
+		return
+	}
+
+	expected, _ := filepath.Abs("../test/fixture-dependency/vpc")
+	assert.Equal(t, expected, terragruntConfig.DependencyList[0].Path)
+	assert.Equal(t, []string{expected}, terragruntConfig.Dependencies.Paths)
+}
+
+func TestMergeDependencyList(t *testing.T) {
+	t.Parallel()
+
+	list := DependencyList{{Name: "vpc", Path: "../vpc"}}
+	list.merge(DependencyList{{Name: "vpc", Path: "../../vpc"}, {Name: "db", Path: "../db"}})
+	assert.Equal(t, DependencyList{{Name: "vpc", Path: "../vpc"}, {Name: "db", Path: "../db"}}, list)
+}
@@ -2,12 +2,16 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/coveo/gotemplate/collections"
 	"github.com/gruntwork-io/terragrunt/aws_helper"
@@ -24,10 +28,11 @@ const (
 var (
 	interpolationVars                 = `var\.([\p{L}_][\p{L}_\-\d\.]*)\s*`
 	interpolationParameters           = fmt.Sprintf(`(\s*(%s)\s*,?\s*)*`, getVarParams(1))
-	interpolationSyntaxRegex          = regexp.MustCompile(fmt.Sprintf(`\$\{\s*(\w+\(%s\)|%s)\s*\}`, interpolationParameters, interpolationVars))
+	interpolationSelectors            = `(?:\.[\p{L}_][\p{L}_\-\d]*)*`
+	interpolationSyntaxRegex          = regexp.MustCompile(fmt.Sprintf(`\$\{\s*(\w+\(%s\)%s|%s)\s*\}`, interpolationParameters, interpolationSelectors, interpolationVars))
 	interpolationSyntaxRegexSingle    = regexp.MustCompile(fmt.Sprintf(`"(%s)"`, interpolationSyntaxRegex))
 	interpolationSyntaxRegexRemaining = regexp.MustCompile(`\$\{.*?\}`)
-	helperFunctionSyntaxRegex         = regexp.MustCompile(`^\$\{\s*(.*?)\((.*?)\)\s*\}$`)
+	helperFunctionSyntaxRegex         = regexp.MustCompile(fmt.Sprintf(`^\$\{\s*(.*?)\((.*?)\)(%s)\s*\}$`, interpolationSelectors))
 	helperVarRegex                    = regexp.MustCompile(fmt.Sprintf(`\$\{%s\}`, interpolationVars))
 	maxParentFoldersToCheck           = 100
 )
@@ -137,9 +142,15 @@ func SubstituteVars(str string, terragruntOptions *options.TerragruntOptions) st
 func (context *resolveContext) getHelperFunctions() map[string]interface{} {
 	return map[string]interface{}{
 		"find_in_parent_folders":                   context.findInParentFolders,
-		"path_relative_to_include":                 context.pathRelativeToInclude,
-		"path_relative_from_include":               context.pathRelativeFromInclude,
+		"path_relative_to_include":                 context.pathRelativeToIncludeInternal,
+		"path_relative_from_include":               context.pathRelativeFromIncludeInternal,
 		"discover":                                 context.getDiscoveredValueInternal,
+		"discover_all":                             context.discoverAllInternal,
+		"secret":                                   context.getSecretInternal,
+		"read_terragrunt_config":                   context.readTerragruntConfigInternal,
+		"read_tfvars":                              context.readTfVarsInternal,
+		"read_yaml":                                context.readYAMLInternal,
+		"read_json":                                context.readJSONInternal,
 		"get_env":                                  context.getEnvironmentVariableInternal,
 		"get_current_dir":                          context.getCurrentDir,
 		"get_leaf_dir":                             context.getTfVarsDir,
@@ -147,6 +158,10 @@ func (context *resolveContext) getHelperFunctions() map[string]interface{} {
 		"get_parent_dir":                           context.getParentTfVarsDir,
 		"get_parent_tfvars_dir":                    context.getParentTfVarsDir,
 		"get_aws_account_id":                       context.getAWSAccountID,
+		"get_aws_caller_identity_arn":              context.getAWSCallerIdentityArn,
+		"get_aws_caller_identity_user_id":          context.getAWSCallerIdentityUserID,
+		"get_aws_region":                           context.getAWSRegion,
+		"get_aws_partition":                        context.getAWSPartition,
 		"get_terraform_commands_that_need_vars":    func() interface{} { return collections.AsList(TerraformCommandWithVarFile) },
 		"get_terraform_commands_that_need_locking": func() interface{} { return collections.AsList(TerraformCommandWithLockTimeout) },
 		"get_terraform_commands_that_need_input":   func() interface{} { return collections.AsList(TerraformCommandWithInput) },
@@ -156,26 +171,36 @@ func (context *resolveContext) getHelperFunctions() map[string]interface{} {
 // Execute a single Terragrunt helper function and return its value as a string
 func (context *resolveContext) executeTerragruntHelperFunction(functionName string, parameters string) (result interface{}, err error) {
 	defer func() {
-		context.options.Logger.Debugf("%s(%s) = %v, %v", functionName, parameters, result, err)
+		context.options.Logger.Debugf("%s(%s) = %v, %v", functionName, parameters, context.options.Cache.RedactSecrets(fmt.Sprint(result)), err)
 	}()
 
 	if functionMap == nil {
 		// We only initialize the function mapping on the first call
 		functionMap = map[string]interface{}{
-			"find_in_parent_folders":                   (*resolveContext).findInParentFolders,
-			"path_relative_to_include":                 (*resolveContext).pathRelativeToInclude,
-			"path_relative_from_include":               (*resolveContext).pathRelativeFromInclude,
-			"get_env":                                  (*resolveContext).getEnvironmentVariable,
-			"default":                                  (*resolveContext).getDefaultValue,
-			"discover":                                 (*resolveContext).getDiscoveredValue,
-			"get_current_dir":                          (*resolveContext).getCurrentDir,
-			"get_leaf_dir":                             (*resolveContext).getTfVarsDir,
-			"get_tfvars_dir":                           (*resolveContext).getTfVarsDir,
-			"get_parent_dir":                           (*resolveContext).getParentTfVarsDir,
-			"get_parent_tfvars_dir":                    (*resolveContext).getParentTfVarsDir,
-			"get_aws_account_id":                       (*resolveContext).getAWSAccountID,
-			"save_variables":                           (*resolveContext).saveVariables,
-			"get_terraform_commands_that_need_vars":    TerraformCommandWithVarFile,
+			"find_in_parent_folders":                (*resolveContext).findInParentFolders,
+			"path_relative_to_include":              (*resolveContext).pathRelativeToInclude,
+			"path_relative_from_include":            (*resolveContext).pathRelativeFromInclude,
+			"get_env":                               (*resolveContext).getEnvironmentVariable,
+			"default":                               (*resolveContext).getDefaultValue,
+			"discover":                              (*resolveContext).getDiscoveredValue,
+			"discover_all":                          (*resolveContext).discoverAll,
+			"secret":                                (*resolveContext).getSecret,
+			"read_terragrunt_config":                (*resolveContext).readTerragruntConfig,
+			"read_tfvars":                           (*resolveContext).readTfVars,
+			"read_yaml":                             (*resolveContext).readYAML,
+			"read_json":                             (*resolveContext).readJSON,
+			"get_current_dir":                       (*resolveContext).getCurrentDir,
+			"get_leaf_dir":                          (*resolveContext).getTfVarsDir,
+			"get_tfvars_dir":                        (*resolveContext).getTfVarsDir,
+			"get_parent_dir":                        (*resolveContext).getParentTfVarsDir,
+			"get_parent_tfvars_dir":                 (*resolveContext).getParentTfVarsDir,
+			"get_aws_account_id":                    (*resolveContext).getAWSAccountID,
+			"get_aws_caller_identity_arn":           (*resolveContext).getAWSCallerIdentityArn,
+			"get_aws_caller_identity_user_id":       (*resolveContext).getAWSCallerIdentityUserID,
+			"get_aws_region":                        (*resolveContext).getAWSRegion,
+			"get_aws_partition":                     (*resolveContext).getAWSPartition,
+			"save_variables":                        (*resolveContext).saveVariables,
+			"get_terraform_commands_that_need_vars": TerraformCommandWithVarFile,
 			"get_terraform_commands_that_need_locking": TerraformCommandWithLockTimeout,
 			"get_terraform_commands_that_need_input":   TerraformCommandWithInput,
 			"get_temp_folder":                          getTempFolder,
@@ -231,6 +256,8 @@ func (context *resolveContext) processSingleInterpolationInString(terragruntConf
 			return fmt.Sprintf(`"%s"`, out)
 		case []string:
 			return util.CommaSeparatedStrings(out)
+		case map[string]interface{}:
+			return hclLiteral(out)
 		default:
 			return fmt.Sprintf("%v", out)
 		}
@@ -238,6 +265,33 @@ func (context *resolveContext) processSingleInterpolationInString(terragruntConf
 	return
 }
 
+// Converts a value returned by an interpolation function into its HCL representation (maps are rendered as objects)
+func hclLiteral(value interface{}) string {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, key := range keys {
+			items[i] = fmt.Sprintf("%s = %s", strconv.Quote(key), hclLiteral(value[key]))
+		}
+		return fmt.Sprintf("{ %s }", strings.Join(items, ", "))
+	case []interface{}:
+		items := make([]string, len(value))
+		for i := range value {
+			items[i] = hclLiteral(value[i])
+		}
+		return fmt.Sprintf("[%s]", strings.Join(items, ", "))
+	case string:
+		return strconv.Quote(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
 // For all interpolation functions that are called using the syntax "${function_a()}-${function_b()}" (i.e. multiple interpolation function
 // within the same string) or "Some text ${function_name()}" (i.e. string composition), we just replace the interpolation function call
 // by the string representation of its return.
@@ -342,8 +396,14 @@ func (context *resolveContext) resolveTerragruntInterpolation(str string) (inter
 	}
 
 	matches := helperFunctionSyntaxRegex.FindStringSubmatch(str)
-	if len(matches) == 3 {
-		return context.executeTerragruntHelperFunction(matches[1], matches[2])
+	if len(matches) == 4 {
+		result, err := context.executeTerragruntHelperFunction(matches[1], matches[2])
+		if err != nil || matches[3] == "" {
+			return result, err
+		}
+		// The function is followed by attributes to select in its result (i.e. ${read_tfvars("file").name})
+		result, err = selectAttributes(result, matches[3])
+		return result, errors.WithStackTrace(err)
 	}
 
 	return "", errors.WithStackTrace(invalidInterpolationSyntax(str))
@@ -372,7 +432,7 @@ func (context *resolveContext) getTfVarsDir() (interface{}, error) {
 
 // Return the parent directory where the Terragrunt configuration file lives
 func (context *resolveContext) getParentTfVarsDir() (interface{}, error) {
-	parentPath, err := context.pathRelativeFromInclude()
+	parentPath, err := context.pathRelativeFromIncludeInternal()
 	if err != nil {
 		return "", err
 	}
@@ -450,7 +510,11 @@ func (context *resolveContext) getDiscoveredValue() (result interface{}, err err
 }
 
 func (context *resolveContext) getDiscoveredValueInternal(key, region string) (result interface{}, err error) {
-	result, err = aws_helper.GetSSMParameter(key, region)
+	// The value could be a secret, so it is only kept in memory
+	cacheKey, _ := awsCacheKey(context.options, "discover", key, region)
+	result, err = context.options.Cache.Memoize(cacheKey, false, func() (interface{}, error) {
+		return aws_helper.GetSSMParameter(key, region)
+	})
 	if err != nil {
 		account, _ := context.getAWSAccountID()
 		err = fmt.Errorf("%s (key=%s region=%s account=%s)", err, key, region, account)
@@ -458,6 +522,53 @@ func (context *resolveContext) getDiscoveredValueInternal(key, region string) (r
 	return
 }
 
+// Loads all the parameters under the path from the parameter store as variables and returns them as a map
+//     discover_all(path, region)
+func (context *resolveContext) discoverAll() (interface{}, error) {
+	parameters, err := context.getParameters(p2Regex)
+	if err != nil || parameters[0] == "" {
+		return "", invalidDiscoverAllParameters(context.parameters)
+	}
+	return context.discoverAllInternal(parameters[0], parameters[1])
+}
+
+func (context *resolveContext) discoverAllInternal(path, region string) (interface{}, error) {
+	return DiscoverAll(path, region, context.options)
+}
+
+type invalidDiscoverAllParameters string
+
+func (err invalidDiscoverAllParameters) Error() string {
+	return fmt.Sprintf("Invalid parameters. Expected discover_all(path, region) but got '%s'", string(err))
+}
+
+// Returns the value of a secret through the resolver registered for its scheme (see secret_resolvers.go)
+//     secret("scheme://path#key")
+func (context *resolveContext) getSecret() (interface{}, error) {
+	parameters, err := context.getParameters(p1Regex)
+	if err != nil || parameters[0] == "" {
+		return "", invalidSecretParameters(context.parameters)
+	}
+	return context.getSecretInternal(parameters[0])
+}
+
+func (context *resolveContext) getSecretInternal(reference string) (interface{}, error) {
+	// The relative paths of the files are relative to the file being processed (as for read_tfvars)
+	if parsed, err := ParseSecretReference(reference); err == nil && parsed.Scheme == "file" {
+		if parsed.Path, err = context.readPath(parsed.Path); err != nil {
+			return nil, err
+		}
+		reference = parsed.String()
+	}
+	return ResolveSecret(reference, context.options)
+}
+
+type invalidSecretParameters string
+
+func (err invalidSecretParameters) Error() string {
+	return fmt.Sprintf("Invalid parameters. Expected secret(\"scheme://path#key\") but got '%s'", string(err))
+}
+
 type invalidDiscoveryParameters string
 
 func (err invalidDiscoveryParameters) Error() string {
@@ -534,46 +645,164 @@ func (err checkedTooManyParentFolders) Error() string {
 
 // Return the relative path between the included Terragrunt configuration file and the current Terragrunt configuration
 // file
+//     path_relative_to_include([include_name])
 func (context *resolveContext) pathRelativeToInclude() (interface{}, error) {
-	parent := context.getParentLocalConfigFilesLocation()
+	name, err := context.getIncludeNameParameter()
+	if err != nil {
+		return "", err
+	}
+	return context.pathRelativeToIncludeInternal(name)
+}
+
+func (context *resolveContext) pathRelativeToIncludeInternal(name ...string) (interface{}, error) {
+	parent, err := context.getParentLocalConfigFilesLocation(name...)
+	if err != nil {
+		return "", err
+	}
 	child := filepath.Dir(context.options.TerragruntConfigPath)
 	return util.GetPathRelativeTo(child, parent)
 }
 
 // Return the relative path from the current Terragrunt configuration to the included Terragrunt configuration file
+//     path_relative_from_include([include_name])
 func (context *resolveContext) pathRelativeFromInclude() (interface{}, error) {
-	parent := context.getParentLocalConfigFilesLocation()
+	name, err := context.getIncludeNameParameter()
+	if err != nil {
+		return "", err
+	}
+	return context.pathRelativeFromIncludeInternal(name)
+}
+
+func (context *resolveContext) pathRelativeFromIncludeInternal(name ...string) (interface{}, error) {
+	parent, err := context.getParentLocalConfigFilesLocation(name...)
+	if err != nil {
+		return "", err
+	}
 	child := filepath.Dir(context.options.TerragruntConfigPath)
 	return util.GetPathRelativeTo(parent, child)
 }
 
-func (context *resolveContext) getParentLocalConfigFilesLocation() string {
+// Returns the optional include name specified as parameter of the function
+func (context *resolveContext) getIncludeNameParameter() (string, error) {
+	if strings.TrimSpace(context.parameters) == "" {
+		return "", nil
+	}
+	parameters, err := context.getParameters(p1Regex)
+	if err != nil {
+		return "", invalidIncludeNameParameters(context.parameters)
+	}
+	return parameters[0], nil
+}
+
+// Returns the folder of the current include file (or of the named include if a name is specified). If the include is
+// a remote file, the folder of the first local file that includes it is returned.
+func (context *resolveContext) getParentLocalConfigFilesLocation(name ...string) (string, error) {
 	cursor := &context.include
+	if len(name) > 0 && name[0] != "" {
+		var err error
+		if cursor, err = context.include.findNamedInclude(name[0]); err != nil {
+			return "", err
+		}
+	}
 	for {
 		includePath, _ := ResolveTerragruntConfigString(cursor.Path, context.include, context.options)
 		if cursor.Source == "" {
 			if !path.IsAbs(includePath) {
 				includePath = util.JoinPath(context.options.WorkingDir, includePath)
 			}
-			return filepath.Dir(includePath)
+			return filepath.Dir(includePath), nil
 		}
 		cursor = cursor.isIncludedBy
 	}
 }
 
+type invalidIncludeNameParameters string
+
+func (err invalidIncludeNameParameters) Error() string {
+	return fmt.Sprintf("Invalid parameters. Expected path_relative_to_include([include_name]) but got '%s'", string(err))
+}
+
 // Return the AWS account id associated to the current set of credentials
 func (context *resolveContext) getAWSAccountID() (interface{}, error) {
-	session, err := aws_helper.CreateAwsSession("", "")
+	return context.getCallerIdentity("account")
+}
+
+// Return the ARN of the AWS user or role associated to the current set of credentials
+func (context *resolveContext) getAWSCallerIdentityArn() (interface{}, error) {
+	return context.getCallerIdentity("arn")
+}
+
+// Return the unique id of the AWS user or role associated to the current set of credentials
+func (context *resolveContext) getAWSCallerIdentityUserID() (interface{}, error) {
+	return context.getCallerIdentity("user_id")
+}
+
+// Returns an element (account, arn or user_id) of the identity associated to the current set of credentials
+func (context *resolveContext) getCallerIdentity(element string) (interface{}, error) {
+	cacheKey, identified := awsCacheKey(context.options, "get_caller_identity")
+	identity, err := context.options.Cache.Memoize(cacheKey, identified, func() (interface{}, error) {
+		session, err := aws_helper.CreateAwsSession("", context.options.AwsProfile)
+		if err != nil {
+			return nil, err
+		}
+
+		identity, err := sts.New(session).GetCallerIdentity(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{
+			"account": *identity.Account,
+			"arn":     *identity.Arn,
+			"user_id": *identity.UserId,
+		}, nil
+	})
 	if err != nil {
 		return "", err
 	}
+	return identity.(map[string]interface{})[element], nil
+}
 
-	identity, err := sts.New(session).GetCallerIdentity(nil)
+// Return the AWS region resolved from the environment variables and the profile (the same way as the AWS sessions)
+func (context *resolveContext) getAWSRegion() (interface{}, error) {
+	session, err := aws_helper.CreateAwsSession("", context.options.AwsProfile)
 	if err != nil {
 		return "", err
 	}
+	if session.Config.Region == nil || *session.Config.Region == "" {
+		return "", errors.WithStackTrace(errUndefinedAWSRegion)
+	}
+	return *session.Config.Region, nil
+}
+
+// Return the AWS partition (aws, aws-cn, aws-us-gov) of the current region. If the region is not known, the partition
+// of the current set of credentials is returned.
+func (context *resolveContext) getAWSPartition() (interface{}, error) {
+	if region, err := context.getAWSRegion(); err == nil {
+		if partition, found := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region.(string)); found {
+			return partition.ID(), nil
+		}
+	}
+
+	arn, err := context.getCallerIdentity("arn")
+	if err != nil {
+		return "", err
+	}
+	// The ARN is of the form arn:partition:service:region:account:resource
+	return strings.Split(fmt.Sprint(arn), ":")[1], nil
+}
+
+var errUndefinedAWSRegion = fmt.Errorf("The AWS region is not defined, set AWS_REGION or the region of the profile")
 
-	return *identity.Account, nil
+// Returns the key used to memoize the result of an AWS call. The current credentials (including the profile specified
+// in the options) are part of the key since they could change during the run (i.e. when a role is assumed). The
+// returned boolean indicates if the credentials are identified by the key, the results obtained with the ambient
+// credentials (i.e. an instance profile or the default profile) must not be persisted since the key would be the same
+// for any account.
+func awsCacheKey(terragruntOptions *options.TerragruntOptions, function string, args ...string) (string, bool) {
+	credentials := []string{terragruntOptions.AwsProfile, os.Getenv("AWS_PROFILE"), os.Getenv("AWS_DEFAULT_PROFILE"), os.Getenv("AWS_ACCESS_KEY_ID")}
+	identified := strings.Join(credentials, "") != ""
+	return fmt.Sprintf("%s(%s)@%s", function, strings.Join(args, ", "), strings.Join(credentials, "/")), identified
 }
 
 func (context *resolveContext) getParameters(regex *regexp.Regexp) ([]string, error) {
@@ -700,3 +700,40 @@ func Test_getParameters(t *testing.T) {
 		})
 	}
 }
+
+// This test modifies the environment variables, so it is not run in parallel
+func TestAWSCacheKey(t *testing.T) {
+	variables := []string{"AWS_PROFILE", "AWS_DEFAULT_PROFILE", "AWS_ACCESS_KEY_ID"}
+	for _, variable := range variables {
+		defer os.Setenv(variable, os.Getenv(variable))
+		os.Unsetenv(variable)
+	}
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("")
+
+	// The ambient credentials are not identified by the key
+	ambientKey, identified := awsCacheKey(terragruntOptions, "get_caller_identity")
+	assert.False(t, identified)
+
+	os.Setenv("AWS_ACCESS_KEY_ID", "AKIAEXAMPLE")
+	key, identified := awsCacheKey(terragruntOptions, "get_caller_identity")
+	assert.True(t, identified)
+	assert.NotEqual(t, ambientKey, key)
+	os.Unsetenv("AWS_ACCESS_KEY_ID")
+
+	// The profile specified in the options identifies the credentials
+	terragruntOptions.AwsProfile = "dev"
+	devKey, identified := awsCacheKey(terragruntOptions, "get_caller_identity")
+	assert.True(t, identified)
+	assert.NotEqual(t, ambientKey, devKey)
+
+	terragruntOptions.AwsProfile = "prod"
+	prodKey, identified := awsCacheKey(terragruntOptions, "get_caller_identity")
+	assert.True(t, identified)
+	assert.NotEqual(t, devKey, prodKey)
+
+	// The arguments are also part of the key
+	key, _ = awsCacheKey(terragruntOptions, "discover", "/prod/terragrunt/key", "us-east-1")
+	otherKey, _ := awsCacheKey(terragruntOptions, "discover", "/prod/terragrunt/key", "us-west-2")
+	assert.NotEqual(t, key, otherKey)
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/hcl/hcl/ast"
+)
+
+// Returns an error if the includes are not valid (several includes must be named with distinct names)
+func (list IncludeList) validate() error {
+	names := make(map[string]bool, len(list))
+	for _, include := range list {
+		if names[include.Name] || include.Name == "" && len(list) > 1 {
+			return DuplicateInclude(include.Name)
+		}
+		names[include.Name] = true
+		if err := include.validateMergeStrategies(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the named includes defined in the configuration string. The path and source of the includes are resolved,
+// but the included files are not read. The configuration that cannot be parsed is ignored (the error will be reported
+// when the configuration will be decoded).
+func findNamedIncludes(configString string, include IncludeConfig, terragruntOptions *options.TerragruntOptions) (map[string]*IncludeConfig, error) {
+	list, problem := terragruntBlock(include.Path, configString)
+	if problem != nil {
+		return nil, nil
+	}
+
+	var result map[string]*IncludeConfig
+	for _, item := range list.Filter("include").Items {
+		object, isObject := item.Val.(*ast.ObjectType)
+		if len(item.Keys) == 0 || !isObject {
+			continue
+		}
+
+		named := &IncludeConfig{Name: keyValue(item.Keys[0]), isIncludedBy: &include}
+		for _, attribute := range object.List.Items {
+			literal, isLiteral := attribute.Val.(*ast.LiteralType)
+			if !isLiteral {
+				continue
+			}
+			switch keyValue(attribute.Keys[0]) {
+			case "path":
+				named.Path = fmt.Sprint(literal.Token.Value())
+			case "source":
+				named.Source = fmt.Sprint(literal.Token.Value())
+			}
+		}
+
+		var err error
+		if named.Path, err = ResolveTerragruntConfigString(named.Path, include, terragruntOptions); err != nil {
+			return nil, err
+		}
+		if named.Source, err = ResolveTerragruntConfigString(named.Source, include, terragruntOptions); err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(named.Path) && named.Source == "" {
+			named.Path = util.JoinPath(filepath.Dir(include.Path), named.Path)
+		}
+
+		if result == nil {
+			result = make(map[string]*IncludeConfig)
+		}
+		result[named.Name] = named
+	}
+	return result, nil
+}
+
+// Returns the named include of the file currently processed or of the files that include it
+func (include *IncludeConfig) findNamedInclude(name string) (*IncludeConfig, error) {
+	for cursor := include; cursor != nil; cursor = cursor.isIncludedBy {
+		if named := cursor.namedIncludes[name]; named != nil {
+			return named, nil
+		}
+	}
+	return nil, UnknownInclude(name)
+}
+
+// DuplicateInclude is the error returned when several includes have the same name (or are not named)
+type DuplicateInclude string
+
+func (err DuplicateInclude) Error() string {
+	if err == "" {
+		return "Several include blocks are defined, they must all be named (include \"name\" { ... })"
+	}
+	return fmt.Sprintf("Duplicate include %q", string(err))
+}
+
+// UnknownInclude is the error returned when a function refers to an include that is not defined
+type UnknownInclude string
+
+func (err UnknownInclude) Error() string {
+	return fmt.Sprintf("No include named %q is defined", string(err))
+}
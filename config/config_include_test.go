@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMultipleIncludes(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-multiple-includes/region/app/" + DefaultTerragruntConfigPath)
+	conf, err := ReadTerragruntConfig(terragruntOptions)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if assert.NotNil(t, conf.RemoteState) {
+		assert.Equal(t, map[string]interface{}{"bucket": "account-bucket", "key": "region/app/terraform.tfstate"}, conf.RemoteState.Config)
+	}
+	// The includes are merged in order
+	assert.Equal(t, []string{"account", "region", "app"}, hookNames(conf.PreHooks))
+	assert.Equal(t, []string{"region/app", ".."}, conf.PreHooks[2].Arguments)
+	assert.Len(t, conf.Files(), 3)
+}
+
+func TestParseInvalidIncludes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		config   string
+		expected error
+	}{
+		{
+			`include { path = "a.tfvars" }
+			 include { path = "b.tfvars" }`,
+			DuplicateInclude(""),
+		},
+		{
+			`include "a" { path = "a.tfvars" }
+			 include { path = "b.tfvars" }`,
+			DuplicateInclude(""),
+		},
+		{
+			`include "a" { path = "a.tfvars" }
+			 include "a" { path = "b.tfvars" }`,
+			DuplicateInclude("a"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, err := parseConfigString("terragrunt = {\n"+testCase.config+"\n}", mockOptions, mockDefaultInclude)
+		assert.Equal(t, testCase.expected, errors.Unwrap(err), testCase.config)
+	}
+}
+
+func TestPathRelativeToUnknownInclude(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terragrunt = {
+  include "parent" {
+    path = "../terraform.tfvars"
+  }
+
+  description = "${path_relative_to_include("missing")}"
+}
+`
+	_, err := parseConfigString(config, mockOptions, mockDefaultInclude)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), UnknownInclude("missing").Error())
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The strategies that could be used to merge a section of an included configuration into the current configuration
+const (
+	ShallowMerge = "shallow" // The default behavior (see mergeIncludedConfig)
+	DeepMerge    = "deep"    // Same as shallow, but the remote_state config keys are merged individually
+	ReplaceMerge = "replace" // The section of the included configuration is ignored
+)
+
+var mergeStrategies = []string{ShallowMerge, DeepMerge, ReplaceMerge}
+
+// The sections of the configuration for which the merge strategy could be specified in merge_strategies
+var mergeSections = []string{
+	"description",
+	"remote_state",
+	"terraform",
+	"dependencies",
+	"dependency",
+	"concurrency_limits",
+	"protect",
+	"run_conditions",
+	"import_files",
+	"extra_command",
+	"approval_config",
+	"pre_hook",
+	"post_hook",
+}
+
+// Returns the strategy to use to merge the section of the included configuration (the section specific strategy if
+// defined, then the global merge_strategy and finally the shallow strategy)
+func (include IncludeConfig) mergeStrategy(section string) string {
+	if strategy := include.MergeStrategies[section]; strategy != "" {
+		return strategy
+	}
+	if include.MergeStrategy != "" {
+		return include.MergeStrategy
+	}
+	return ShallowMerge
+}
+
+// Returns an error if the merge strategies of the include are not valid
+func (include IncludeConfig) validateMergeStrategies() error {
+	if include.MergeStrategy != "" {
+		if err := validateMergeStrategy("", include.MergeStrategy); err != nil {
+			return err
+		}
+	}
+	sections := make([]string, 0, len(include.MergeStrategies))
+	for section := range include.MergeStrategies {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	for _, section := range sections {
+		if err := validateMergeStrategy(section, include.MergeStrategies[section]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns an error if the strategy is not valid for the section (an empty section refers to the global merge_strategy)
+func validateMergeStrategy(section, strategy string) error {
+	if section != "" && !util.ListContainsElement(mergeSections, section) {
+		return UnknownMergeSection(section)
+	}
+	if !util.ListContainsElement(mergeStrategies, strategy) {
+		return InvalidMergeStrategy{section, strategy}
+	}
+	return nil
+}
+
+// Returns the remote state resulting of the deep merge of the included remote state into the current one. The config
+// keys defined in the current remote state have precedence. The included remote state is ignored if it uses another
+// backend.
+func deepMergeRemoteState(current, included *remote.RemoteState) *remote.RemoteState {
+	if current == nil {
+		return included
+	}
+	if included == nil || included.Backend != current.Backend {
+		return current
+	}
+
+	merged := &remote.RemoteState{
+		Backend: current.Backend,
+		Config:  make(map[string]interface{}, len(current.Config)+len(included.Config)),
+	}
+	for key, value := range included.Config {
+		merged.Config[key] = value
+	}
+	for key, value := range current.Config {
+		merged.Config[key] = value
+	}
+	return merged
+}
+
+// Indicates if the remote state keys of the included configuration are merged into the current configuration
+func remoteStateKeysMerged(current, included *remote.RemoteState, strategy string) bool {
+	switch strategy {
+	case ReplaceMerge:
+		return false
+	case DeepMerge:
+		return current == nil || included != nil && included.Backend == current.Backend
+	default:
+		return current == nil
+	}
+}
+
+// Returns the section of the configuration corresponding to the key path (i.e. pre_hook.name => pre_hook)
+func keySection(key string) string {
+	return strings.SplitN(key, ".", 2)[0]
+}
+
+// UnknownMergeSection is the error returned when merge_strategies refers to a section that cannot be merged
+type UnknownMergeSection string
+
+func (err UnknownMergeSection) Error() string {
+	return fmt.Sprintf("Unknown section %s in merge_strategies, must be one of %s", string(err), strings.Join(mergeSections, ", "))
+}
+
+// InvalidMergeStrategy is the error returned when a merge strategy is not supported
+type InvalidMergeStrategy struct {
+	Section  string
+	Strategy string
+}
+
+func (err InvalidMergeStrategy) Error() string {
+	attribute := "merge_strategy"
+	if err.Section != "" {
+		attribute = fmt.Sprintf("merge_strategies.%s", err.Section)
+	}
+	return fmt.Sprintf("Invalid %s %q, must be one of %s", attribute, err.Strategy, strings.Join(mergeStrategies, ", "))
+}
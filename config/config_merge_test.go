@@ -0,0 +1,112 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/stretchr/testify/assert"
+)
+
+func hookNames(hooks HookList) (names []string) {
+	for _, hook := range hooks {
+		names = append(names, hook.Name)
+	}
+	return
+}
+
+func TestMergeStrategyDeep(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-merge-strategies/deep/" + DefaultTerragruntConfigPath)
+	conf, err := ReadTerragruntConfig(terragruntOptions)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, &remote.RemoteState{
+		Backend: "s3",
+		Config: map[string]interface{}{
+			"bucket": "my-bucket",
+			"key":    "deep/terraform.tfstate",
+			"region": "us-east-1",
+		},
+	}, conf.RemoteState)
+	assert.Equal(t, OriginOverridden, conf.Origin("remote_state.config.key").Status)
+	assert.Equal(t, OriginMerged, conf.Origin("remote_state.config.bucket").Status)
+
+	// The pre hooks are replaced while the post hooks are merged with the default strategy
+	assert.Equal(t, []string{"child"}, hookNames(conf.PreHooks))
+	assert.Equal(t, []string{"parent"}, hookNames(conf.PostHooks))
+	assert.Nil(t, conf.Origin("pre_hook.parent"))
+}
+
+func TestMergeStrategyReplace(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-merge-strategies/replace/" + DefaultTerragruntConfigPath)
+	conf, err := ReadTerragruntConfig(terragruntOptions)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Nil(t, conf.RemoteState)
+	assert.Empty(t, conf.PreHooks)
+	assert.Equal(t, []string{"child"}, hookNames(conf.PostHooks))
+}
+
+func TestDeepMergeRemoteState(t *testing.T) {
+	t.Parallel()
+
+	s3 := func(config map[string]interface{}) *remote.RemoteState {
+		return &remote.RemoteState{Backend: "s3", Config: config}
+	}
+	gcs := &remote.RemoteState{Backend: "gcs", Config: map[string]interface{}{"bucket": "gcs"}}
+
+	testCases := []struct {
+		current  *remote.RemoteState
+		included *remote.RemoteState
+		expected *remote.RemoteState
+	}{
+		{nil, nil, nil},
+		{nil, gcs, gcs},
+		{gcs, nil, gcs},
+		{gcs, s3(map[string]interface{}{"key": "a"}), gcs},
+		{
+			s3(map[string]interface{}{"key": "a"}),
+			s3(map[string]interface{}{"key": "b", "bucket": "b"}),
+			s3(map[string]interface{}{"key": "a", "bucket": "b"}),
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, deepMergeRemoteState(testCase.current, testCase.included))
+	}
+}
+
+func TestInvalidMergeStrategies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		config   string
+		expected error
+	}{
+		{`merge_strategy = "merge"`, InvalidMergeStrategy{"", "merge"}},
+		{`merge_strategies { pre_hook = "none" }`, InvalidMergeStrategy{"pre_hook", "none"}},
+		{`merge_strategies { timeout = "deep" }`, UnknownMergeSection("timeout")},
+	}
+
+	for _, testCase := range testCases {
+		config := `
+terragrunt = {
+  include {
+    path = "../terraform.tfvars"
+    ` + testCase.config + `
+  }
+}
+`
+		_, err := parseConfigString(config, mockOptions, mockDefaultInclude)
+		assert.Equal(t, testCase.expected, errors.Unwrap(err), testCase.config)
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// ProtectConfig defines resources that should not be destroyed or replaced by the *-all commands. The resources are
+// identified by glob patterns matching their address (i.e. module.db.aws_rds_cluster.main) or their type
+// (i.e. aws_rds_*).
+type ProtectConfig struct {
+	Resources      []string `hcl:"resources"`
+	AllowedActions []string `hcl:"allowed_actions"`
+}
+
+// ProtectList represents the list of protect blocks
+type ProtectList []ProtectConfig
+
+// The actions allowed on protected resources if allowed_actions is not specified
+var defaultAllowedActions = []string{"create", "update"}
+
+// The actions that could be planned on a resource
+var protectActions = []string{"create", "update", "delete", "replace"}
+
+func (protect ProtectConfig) String() string {
+	return fmt.Sprintf("protect %v (allowed actions: %v)", protect.Resources, protect.allowedActions())
+}
+
+func (protect ProtectConfig) allowedActions() []string {
+	if protect.AllowedActions == nil {
+		return defaultAllowedActions
+	}
+	return protect.AllowedActions
+}
+
+// Validate returns an error if an allowed action is not a valid action
+func (list ProtectList) Validate() error {
+	for _, protect := range list {
+		for _, action := range protect.AllowedActions {
+			if !util.ListContainsElement(protectActions, action) {
+				return InvalidProtectAction{action}
+			}
+		}
+	}
+	return nil
+}
+
+// Protects returns the pattern of the first protect block forbidding the action on the resource (empty if the
+// action is allowed)
+func (list ProtectList) Protects(address, action string) string {
+	for _, protect := range list {
+		if util.ListContainsElement(protect.allowedActions(), action) {
+			continue
+		}
+		for _, pattern := range protect.Resources {
+			if matchResource(pattern, address) {
+				return pattern
+			}
+		}
+	}
+	return ""
+}
+
+// Matches the modules and the index of the resource address, i.e. module.a.module.b.aws_instance.name[0]
+var resourceAddressRegex = regexp.MustCompile(`^(?:module\.[^.]+\.)*(?:data\.)?([^.]+)\.[^.]+$`)
+
+// Returns true if the pattern matches the resource address or its type
+func matchResource(pattern, address string) bool {
+	if match, _ := path.Match(pattern, address); match {
+		return true
+	}
+	address = strings.Split(address, "[")[0]
+	if match := resourceAddressRegex.FindStringSubmatch(address); match != nil {
+		matchType, _ := path.Match(pattern, match[1])
+		return matchType
+	}
+	return false
+}
+
+// InvalidProtectAction is the error returned when an allowed action of a protect block is not valid
+type InvalidProtectAction struct {
+	Action string
+}
+
+func (err InvalidProtectAction) Error() string {
+	return fmt.Sprintf("Invalid allowed action %q in protect block, must be one of %v", err.Action, protectActions)
+}
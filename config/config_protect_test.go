@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectListProtects(t *testing.T) {
+	t.Parallel()
+
+	protect := ProtectList{
+		{Resources: []string{"aws_rds_*", "aws_s3_bucket.logs"}},
+		{Resources: []string{"aws_instance.*"}, AllowedActions: []string{"create", "update", "replace"}},
+	}
+
+	testCases := []struct {
+		address  string
+		action   string
+		expected string
+	}{
+		{"aws_rds_cluster.main", "delete", "aws_rds_*"},
+		{"aws_rds_cluster.main", "replace", "aws_rds_*"},
+		{"aws_rds_cluster.main", "update", ""},
+		{"module.db.aws_rds_cluster.main[0]", "delete", "aws_rds_*"},
+		{"aws_s3_bucket.logs", "delete", "aws_s3_bucket.logs"},
+		{"aws_s3_bucket.data", "delete", ""},
+		{"aws_instance.web", "replace", ""},
+		{"aws_instance.web", "delete", "aws_instance.*"},
+		{"aws_security_group.web", "delete", ""},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, protect.Protects(testCase.address, testCase.action), "For %s %s", testCase.action, testCase.address)
+	}
+}
+
+func TestParseTerragruntConfigProtect(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terragrunt = {
+  protect {
+    resources = ["aws_rds_*"]
+  }
+  protect {
+    resources       = ["aws_instance.*"]
+    allowed_actions = ["create", "update", "replace"]
+  }
+}
+`
+
+	terragruntConfig, err := parseConfigString(config, mockOptions, mockDefaultInclude)
+	if assert.Nil(t, err) {
+		assert.Equal(t, ProtectList{
+			{Resources: []string{"aws_rds_*"}},
+			{Resources: []string{"aws_instance.*"}, AllowedActions: []string{"create", "update", "replace"}},
+		}, terragruntConfig.Protect)
+	}
+}
+
+func TestParseTerragruntConfigProtectInvalidAction(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terragrunt = {
+  protect {
+    resources       = ["aws_rds_*"]
+    allowed_actions = ["destroy"]
+  }
+}
+`
+
+	_, err := parseConfigString(config, mockOptions, mockDefaultInclude)
+	assert.Equal(t, InvalidProtectAction{"destroy"}, errors.Unwrap(err))
+}
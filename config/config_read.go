@@ -0,0 +1,177 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/coveo/gotemplate/hcl"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Returns the resolved configuration of another Terragrunt configuration file (or folder) as a document using the
+// names of the configuration attributes
+//     read_terragrunt_config(path)
+func (context *resolveContext) readTerragruntConfig() (interface{}, error) {
+	parameters, err := context.getParameters(p1Regex)
+	if err != nil || parameters[0] == "" {
+		return "", invalidReadParameters{"read_terragrunt_config", context.parameters}
+	}
+	return context.readTerragruntConfigInternal(parameters[0])
+}
+
+func (context *resolveContext) readTerragruntConfigInternal(path string) (interface{}, error) {
+	path, err := context.readPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if stat, _ := os.Stat(path); stat != nil && stat.IsDir() {
+		path = DefaultConfigPath(path)
+	}
+
+	// The files being read are tracked to detect the configurations reading each other (directly or not)
+	reading := append([]string{}, context.options.ReadingConfigs...)
+	if current, err := util.CanonicalPath(context.options.TerragruntConfigPath, ""); err == nil {
+		reading = append(reading, current)
+	}
+	if util.ListContainsElement(reading, path) {
+		return nil, errors.WithStackTrace(readConfigCycle(append(reading, path)))
+	}
+
+	// The configuration is parsed with its own options to avoid importing its variables in the current configuration,
+	// the already parsed configurations are taken from the cache
+	readOptions := context.options.Clone(path)
+	readOptions.ReadingConfigs = reading
+	conf, err := ParseConfigFile(readOptions, IncludeConfig{Path: path})
+	if err != nil {
+		return nil, err
+	}
+	document, _ := configRenderer{}.render(reflect.ValueOf(*conf), "").(map[string]interface{})
+	if document == nil {
+		document = map[string]interface{}{}
+	}
+	return document, nil
+}
+
+// Returns the variables defined in a tfvars, yaml or json file, each function only accepts the format of its name
+//     read_tfvars(path), read_yaml(path), read_json(path)
+func (context *resolveContext) readTfVars() (interface{}, error) {
+	return context.readVariables("read_tfvars")
+}
+
+func (context *resolveContext) readYAML() (interface{}, error) {
+	return context.readVariables("read_yaml")
+}
+
+func (context *resolveContext) readJSON() (interface{}, error) {
+	return context.readVariables("read_json")
+}
+
+func (context *resolveContext) readVariables(function string) (interface{}, error) {
+	parameters, err := context.getParameters(p1Regex)
+	if err != nil || parameters[0] == "" {
+		return "", invalidReadParameters{function, context.parameters}
+	}
+	return context.readVariablesInternal(function, parameters[0])
+}
+
+func (context *resolveContext) readTfVarsInternal(path string) (interface{}, error) {
+	return context.readVariablesInternal("read_tfvars", path)
+}
+
+func (context *resolveContext) readYAMLInternal(path string) (interface{}, error) {
+	return context.readVariablesInternal("read_yaml", path)
+}
+
+func (context *resolveContext) readJSONInternal(path string) (interface{}, error) {
+	return context.readVariablesInternal("read_json", path)
+}
+
+// The parsers of the formats read by the read functions
+var readParsers = map[string]struct {
+	format    string
+	unmarshal func([]byte, interface{}) error
+}{
+	"read_tfvars": {"hcl", hcl.Unmarshal},
+	"read_yaml":   {"yaml", yaml.Unmarshal},
+	"read_json":   {"json", json.Unmarshal},
+}
+
+func (context *resolveContext) readVariablesInternal(function, path string) (interface{}, error) {
+	path, err := context.readPath(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	parser := readParsers[function]
+	variables := map[string]interface{}{}
+	if err := parser.unmarshal(content, &variables); err != nil {
+		return nil, errors.WithStackTrace(invalidReadFormat{function, parser.format, path, err})
+	}
+	// The yaml documents could contain maps with non string keys
+	return jsonValue(variables), nil
+}
+
+// Returns the path of a file read by a function, the relative paths are relative to the folder of the configuration
+// file currently processed
+func (context *resolveContext) readPath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	folder, err := context.getParentLocalConfigFilesLocation()
+	if err != nil {
+		return "", err
+	}
+	return util.CanonicalPath(path, folder)
+}
+
+// Returns the value of the attributes (i.e. .a.b) in the result of a function
+func selectAttributes(value interface{}, selectors string) (interface{}, error) {
+	for _, attribute := range strings.Split(strings.TrimPrefix(selectors, "."), ".") {
+		if value = mapValue(value, attribute); value == nil {
+			return nil, undefinedAttribute(selectors)
+		}
+	}
+	return value, nil
+}
+
+type invalidReadParameters struct {
+	function   string
+	parameters string
+}
+
+func (err invalidReadParameters) Error() string {
+	return fmt.Sprintf("Invalid parameters. Expected %s(path) but got '%s'", err.function, err.parameters)
+}
+
+type invalidReadFormat struct {
+	function string
+	format   string
+	path     string
+	err      error
+}
+
+func (err invalidReadFormat) Error() string {
+	return fmt.Sprintf("%s expects a %s file, unable to parse %s: %v", err.function, err.format, err.path, err.err)
+}
+
+type undefinedAttribute string
+
+func (err undefinedAttribute) Error() string {
+	return fmt.Sprintf("Attribute %s is not defined in the result of the function", strings.TrimPrefix(string(err), "."))
+}
+
+type readConfigCycle []string
+
+func (err readConfigCycle) Error() string {
+	return fmt.Sprintf("Found a cycle between the configurations read by read_terragrunt_config: %s", strings.Join([]string(err), " -> "))
+}
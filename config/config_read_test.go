@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFunctions(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-read-config/app/" + DefaultTerragruntConfigPath)
+	conf, err := ReadTerragruntConfig(terragruntOptions)
+	if !assert.NoError(t, err) || !assert.Len(t, conf.PreHooks, 1) {
+		return
+	}
+
+	expected := []string{"123456789012", "vpc-1234", "platform-team", "git::git@github.com:foo/modules.git//network"}
+	assert.Equal(t, expected, conf.PreHooks[0].Arguments)
+
+	// The variables of the configuration that has been read are not imported
+	_, imported := terragruntOptions.Variables["cidr_block"]
+	assert.False(t, imported)
+}
+
+func TestReadFunctionsErrors(t *testing.T) {
+	t.Parallel()
+
+	include := IncludeConfig{Path: "../test/fixture-read-config/app/" + DefaultTerragruntConfigPath}
+	testCases := []struct {
+		str      string
+		expected error
+	}{
+		{`"${read_tfvars()}"`, invalidReadParameters{"read_tfvars", ""}},
+		{`"${read_terragrunt_config()}"`, invalidReadParameters{"read_terragrunt_config", ""}},
+		{`"${read_tfvars("../account.tfvars").undefined}"`, undefinedAttribute(".undefined")},
+	}
+
+	for _, testCase := range testCases {
+		_, err := ResolveTerragruntConfigString(testCase.str, include, mockOptions)
+		assert.Equal(t, testCase.expected, errors.Unwrap(err), testCase.str)
+	}
+
+	// Each function only accepts the format of its name
+	for _, str := range []string{
+		`"${read_yaml("../account.tfvars").account_id}"`,
+		`"${read_json("../settings.yml").network}"`,
+		`"${read_tfvars("../settings.yml").network}"`,
+	} {
+		_, err := ResolveTerragruntConfigString(str, include, mockOptions)
+		assert.IsType(t, invalidReadFormat{}, errors.Unwrap(err), str)
+	}
+}
+
+func TestReadTerragruntConfigCycle(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-read-config/cycle/a/" + DefaultTerragruntConfigPath)
+	_, err := ReadTerragruntConfig(terragruntOptions)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Found a cycle between the configurations read by read_terragrunt_config")
+	}
+}
+
+func TestSelectAttributes(t *testing.T) {
+	t.Parallel()
+
+	document := map[string]interface{}{
+		"a": map[string]interface{}{"b": "value"},
+		"c": []map[string]interface{}{{"d": 1}},
+	}
+
+	value, err := selectAttributes(document, ".a.b")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	value, err = selectAttributes(document, ".c.d")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	_, err = selectAttributes(document, ".a.b.c")
+	assert.Equal(t, undefinedAttribute(".a.b.c"), err)
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The status of an element of the resolved configuration
+const (
+	OriginDefined    = "defined"    // The element is defined in the configuration file itself
+	OriginMerged     = "merged"     // The element has been merged from an included (or bootstrap) file
+	OriginOverridden = "overridden" // The element is defined in the configuration file and overrides the included ones
+)
+
+// ConfigOrigin describes where an element of the resolved configuration (extension or remote_state key) comes from
+type ConfigOrigin struct {
+	File       string   // The file that defines the element
+	Status     string   // The way the element has been added to the configuration (defined, merged or overridden)
+	Overridden []string // The files that define an element with the same name ignored in favor of this one
+}
+
+func (origin ConfigOrigin) String() string {
+	if len(origin.Overridden) == 0 {
+		return fmt.Sprintf("%s in %s", origin.Status, origin.File)
+	}
+	return fmt.Sprintf("%s in %s (overrides %s)", origin.Status, origin.File, strings.Join(origin.Overridden, ", "))
+}
+
+// Origin returns the origin of an element of the configuration identified by its key path (i.e. pre_hook.name,
+// terraform.extra_arguments.name or remote_state.config.bucket)
+func (conf TerragruntConfig) Origin(key string) *ConfigOrigin {
+	return conf.origins[key]
+}
+
+// Returns the key paths of the elements of the configuration for which the origin is tracked
+func (conf TerragruntConfig) originKeys() (keys []string) {
+	add := func(prefix string, list interface{}) {
+		items := reflect.ValueOf(list)
+		for i := 0; i < items.Len(); i++ {
+			keys = append(keys, joinKeyPath(prefix, IGenericItem(items.Index(i).Addr().Interface()).id()))
+		}
+	}
+	if conf.Terraform != nil {
+		add("terraform.extra_arguments", conf.Terraform.ExtraArgs)
+	}
+	add("pre_hook", conf.PreHooks)
+	add("post_hook", conf.PostHooks)
+	add("extra_command", conf.ExtraCommands)
+	add("import_files", conf.ImportFiles)
+	add("approval_config", conf.ApprovalConfig)
+
+	if conf.RemoteState != nil {
+		keys = append(keys, "remote_state.backend")
+		for key := range conf.RemoteState.Config {
+			keys = append(keys, joinKeyPath("remote_state.config", key))
+		}
+	}
+	return
+}
+
+// Initialize the origin of all the elements defined in the configuration file
+func (conf *TerragruntConfig) initOrigins(file string) {
+	keys := conf.originKeys()
+	conf.origins = make(map[string]*ConfigOrigin, len(keys))
+	for _, key := range keys {
+		conf.origins[key] = &ConfigOrigin{File: file, Status: OriginDefined}
+	}
+}
+
+// Update the origins of the elements according to the included config that is about to be merged. It must be called
+// before the elements are merged since it relies on the elements already defined in the current config.
+func (conf *TerragruntConfig) mergeOrigins(includedConfig TerragruntConfig, include IncludeConfig) {
+	for _, key := range includedConfig.originKeys() {
+		included := includedConfig.origins[key]
+		if included == nil || include.mergeStrategy(keySection(key)) == ReplaceMerge {
+			continue
+		}
+		if origin, exist := conf.origins[key]; exist {
+			if origin.Status == OriginDefined {
+				origin.Status = OriginOverridden
+			}
+			origin.Overridden = append(origin.Overridden, included.File)
+			origin.Overridden = append(origin.Overridden, included.Overridden...)
+			continue
+		}
+		if keySection(key) == "remote_state" && !remoteStateKeysMerged(conf.RemoteState, includedConfig.RemoteState, include.mergeStrategy("remote_state")) {
+			// The remote_state block of the included configuration is entirely ignored
+			continue
+		}
+		if conf.origins == nil {
+			conf.origins = make(map[string]*ConfigOrigin)
+		}
+		merged := *included
+		merged.Status = OriginMerged
+		conf.origins[key] = &merged
+	}
+}
+
+// Render returns the resolved configuration as a document that could be marshalled in HCL, JSON or YAML. Each
+// extension is annotated by an _origin attribute and the remote_state block by an _origins attribute describing the
+// origin of each of its keys. The files are expressed relatively to the base path.
+func (conf TerragruntConfig) Render(basePath string) map[string]interface{} {
+	renderer := configRenderer{conf.origins, basePath}
+	rendered, _ := renderer.render(reflect.ValueOf(conf), "").(map[string]interface{})
+	if rendered == nil {
+		rendered = map[string]interface{}{}
+	}
+
+	if remoteState, _ := rendered["remote_state"].(map[string]interface{}); remoteState != nil {
+		origins := map[string]interface{}{}
+		for key, origin := range conf.origins {
+			if strings.HasPrefix(key, "remote_state.") {
+				origins[strings.TrimPrefix(strings.TrimPrefix(key, "remote_state."), "config.")] = renderer.origin(origin)
+			}
+		}
+		remoteState["_origins"] = origins
+	}
+	return map[string]interface{}{"terragrunt": rendered}
+}
+
+type configRenderer struct {
+	origins  map[string]*ConfigOrigin
+	basePath string
+}
+
+// Converts the value into basic types using the hcl names of the fields. Empty values are omitted and the lists of
+// named blocks are rendered as lists of single key objects to preserve their ordering.
+func (renderer configRenderer) render(value reflect.Value, keyPath string) interface{} {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return renderer.render(value.Elem(), keyPath)
+	case reflect.Struct:
+		result := map[string]interface{}{}
+		renderer.renderFields(value, keyPath, result)
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if value.Len() == 0 {
+			return nil
+		}
+		label := labelField(value.Type().Elem())
+		result := make([]interface{}, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			item := value.Index(i)
+			if label == "" {
+				result = append(result, renderer.render(item, keyPath))
+				continue
+			}
+			name := fmt.Sprint(item.FieldByName(label).Interface())
+			object, _ := renderer.render(item, joinKeyPath(keyPath, name)).(map[string]interface{})
+			if object == nil {
+				object = map[string]interface{}{}
+			}
+			if origin := renderer.origins[joinKeyPath(keyPath, name)]; origin != nil {
+				object["_origin"] = renderer.origin(origin)
+			}
+			result = append(result, map[string]interface{}{name: object})
+		}
+		return result
+	case reflect.Map:
+		if value.Len() == 0 {
+			return nil
+		}
+		result := make(map[string]interface{}, value.Len())
+		for _, key := range value.MapKeys() {
+			result[fmt.Sprint(key.Interface())] = renderer.render(value.MapIndex(key), keyPath)
+		}
+		return result
+	case reflect.String:
+		if value.Len() == 0 {
+			return nil
+		}
+	case reflect.Bool:
+		if !value.Bool() {
+			return nil
+		}
+	}
+	return value.Interface()
+}
+
+func (renderer configRenderer) renderFields(value reflect.Value, keyPath string, result map[string]interface{}) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		tag := strings.Split(field.Tag.Get("hcl"), ",")
+		switch {
+		case field.PkgPath != "":
+			// Unexported field
+		case util.ListContainsElement(tag[1:], "squash"):
+			renderer.renderFields(value.Field(i), keyPath, result)
+		case util.ListContainsElement(tag[1:], "key"):
+			// The block label is rendered as the key of the object
+		default:
+			name := tag[0]
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			// A nil pointer is omitted, but a pointer to a zero value is significant
+			fieldValue := value.Field(i)
+			if fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() && fieldValue.Elem().Kind() != reflect.Struct {
+				result[name] = fieldValue.Elem().Interface()
+			} else if rendered := renderer.render(fieldValue, joinKeyPath(keyPath, name)); rendered != nil {
+				result[name] = rendered
+			}
+		}
+	}
+}
+
+func (renderer configRenderer) origin(origin *ConfigOrigin) map[string]interface{} {
+	result := map[string]interface{}{
+		"file":   renderer.relative(origin.File),
+		"status": origin.Status,
+	}
+	if len(origin.Overridden) > 0 {
+		overridden := make([]string, len(origin.Overridden))
+		for i := range origin.Overridden {
+			overridden[i] = renderer.relative(origin.Overridden[i])
+		}
+		result["overridden"] = overridden
+	}
+	return result
+}
+
+func (renderer configRenderer) relative(file string) string {
+	if relative, err := util.GetPathRelativeTo(file, renderer.basePath); err == nil {
+		return relative
+	}
+	return file
+}
+
+// Returns the name of the field used as block label (hcl:",key") in the struct type (or the types it squashes)
+func labelField(objectType reflect.Type) string {
+	if objectType.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < objectType.NumField(); i++ {
+		field := objectType.Field(i)
+		tag := strings.Split(field.Tag.Get("hcl"), ",")
+		if util.ListContainsElement(tag[1:], "key") {
+			return field.Name
+		}
+		if util.ListContainsElement(tag[1:], "squash") {
+			if label := labelField(field.Type); label != "" {
+				return label
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderConfigOrigins(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-render-config/child/" + DefaultTerragruntConfigPath)
+	conf, err := ReadTerragruntConfig(terragruntOptions)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rendered := conf.Render("../test/fixture-render-config/child")["terragrunt"].(map[string]interface{})
+	origin := func(file, status string, overridden ...string) map[string]interface{} {
+		result := map[string]interface{}{"file": file, "status": status}
+		if len(overridden) > 0 {
+			result["overridden"] = overridden
+		}
+		return result
+	}
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"init": map[string]interface{}{
+			"command": "echo", "order": 0, "_origin": origin("../terraform.tfvars", OriginMerged),
+		}},
+		map[string]interface{}{"check": map[string]interface{}{
+			"command": "echo", "arguments": []interface{}{"child"}, "order": 0,
+			"_origin": origin("terraform.tfvars", OriginOverridden, "../terraform.tfvars"),
+		}},
+	}, rendered["pre_hook"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"notify": map[string]interface{}{
+			"command": "echo", "order": 0, "_origin": origin("terraform.tfvars", OriginDefined),
+		}},
+	}, rendered["post_hook"])
+	assert.Equal(t, map[string]interface{}{
+		"backend": "s3",
+		"config":  map[string]interface{}{"bucket": "my-bucket", "key": "terraform.tfstate"},
+		"_origins": map[string]interface{}{
+			"backend": origin("../terraform.tfvars", OriginMerged),
+			"bucket":  origin("../terraform.tfvars", OriginMerged),
+			"key":     origin("../terraform.tfvars", OriginMerged),
+		},
+	}, rendered["remote_state"])
+	if origin := conf.Origin("terraform.extra_arguments.common"); assert.NotNil(t, origin) {
+		assert.Equal(t, OriginMerged, origin.Status)
+		assert.Equal(t, "terraform.tfvars", filepath.Base(origin.File))
+	}
+}
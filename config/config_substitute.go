@@ -28,6 +28,14 @@ func (conf *TerragruntConfig) SubstituteAllVariables(terragruntOptions *options.
 	}
 
 	substitute(conf.Uniqueness)
+	substitute(conf.Timeout)
+	substitute(conf.Group)
+
+	for i := range conf.Protect {
+		for j := range conf.Protect[i].Resources {
+			substitute(&conf.Protect[i].Resources[j])
+		}
+	}
 
 	if roles, ok := conf.AssumeRole.([]string); ok {
 		for i := range roles {
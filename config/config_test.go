@@ -409,6 +409,23 @@ func TestParseTerragruntConfigThreeLevels(t *testing.T) {
 	assert.NotNil(t, terragruntConfig)
 }
 
+func TestParseTerragruntConfigFiles(t *testing.T) {
+	t.Parallel()
+
+	fixture := "../test/fixture-parent-folders/multiple-terragrunt-in-parents/"
+	configPath := fixture + "child/sub-child/" + DefaultTerragruntConfigPath
+
+	terragruntConfig, err := ParseConfigFile(options.NewTerragruntOptionsForTest(configPath), IncludeConfig{Path: configPath})
+	assert.Nil(t, err)
+
+	var expected []string
+	for _, path := range []string{configPath, fixture + "child/" + DefaultTerragruntConfigPath, fixture + DefaultTerragruntConfigPath} {
+		absolute, _ := util.CanonicalPath(path, "")
+		expected = append(expected, absolute)
+	}
+	assert.Equal(t, expected, terragruntConfig.Files())
+}
+
 func TestParseWithBootStrapFile(t *testing.T) {
 	// Cannot be run in parallel since it defines an environment variable
 	fixture := "../test/fixture-bootstrap/"
@@ -555,7 +572,7 @@ func TestMergeConfigIntoIncludedConfig(t *testing.T) {
 	}
 
 	for _, testCase := range testCases {
-		testCase.config.mergeIncludedConfig(testCase.includedConfig, mockOptions)
+		testCase.config.mergeIncludedConfig(testCase.includedConfig, IncludeConfig{}, mockOptions)
 		assert.Equal(t, testCase.config, testCase.expected, "For config %v and includeConfig %v", testCase.config, testCase.includedConfig)
 	}
 }
@@ -757,6 +774,62 @@ func TestFindConfigFilesInPathMultipleConfigs(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestFindConfigFilesInPathIncludeExcludeDirs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		includeDirs []string
+		excludeDirs []string
+		expected    []string
+	}{
+		{
+			[]string{"subdir-*"}, nil,
+			[]string{"subdir-2/subdir/.terragrunt", "subdir-3/terraform.tfvars"},
+		},
+		{
+			nil, []string{"subdir-2"},
+			[]string{"terraform.tfvars", "subdir-3/terraform.tfvars"},
+		},
+		{
+			[]string{"subdir-*"}, []string{"*/subdir"},
+			[]string{"subdir-3/terraform.tfvars"},
+		},
+		{
+			[]string{"."}, []string{"subdir-3"},
+			[]string{"terraform.tfvars", "subdir-2/subdir/.terragrunt"},
+		},
+	}
+
+	const root = "../test/fixture-config-files/multiple-configs"
+	for _, testCase := range testCases {
+		opts := newOptionsWorkingDir(root)
+		opts.IncludeDirs = testCase.includeDirs
+		opts.ExcludeDirs = testCase.excludeDirs
+
+		expected := make([]string, len(testCase.expected))
+		for i := range testCase.expected {
+			expected[i] = filepath.Join(root, testCase.expected[i])
+		}
+
+		actual, err := FindConfigFilesInPath(opts)
+		assert.Nil(t, err, "Unexpected error: %v", err)
+		assert.Equal(t, expected, actual, "include %v, exclude %v", testCase.includeDirs, testCase.excludeDirs)
+	}
+}
+
+func TestFindConfigFilesInPathInvalidGlob(t *testing.T) {
+	t.Parallel()
+
+	opts := newOptionsWorkingDir("../test/fixture-config-files/multiple-configs")
+	opts.ExcludeDirs = []string{"subdir-["}
+
+	_, err := FindConfigFilesInPath(opts)
+	if assert.NotNil(t, err) {
+		_, isInvalidGlob := errors.Unwrap(err).(InvalidDirGlob)
+		assert.True(t, isInvalidGlob, "Unexpected error: %v", err)
+	}
+}
+
 func newOptionsWorkingDir(workingDir string) *options.TerragruntOptions {
 	opts := options.NewTerragruntOptionsForTest("")
 	opts.WorkingDir = workingDir
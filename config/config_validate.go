@@ -0,0 +1,520 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/coveo/gotemplate/template"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/parser"
+	"github.com/hashicorp/hcl/hcl/token"
+)
+
+// ValidationProblem is a problem found while validating a configuration file
+type ValidationProblem struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (problem ValidationProblem) String() string {
+	switch {
+	case problem.File == "":
+		return problem.Message
+	case problem.Line == 0:
+		return fmt.Sprintf("%s: %s", util.GetPathRelativeToWorkingDir(problem.File), problem.Message)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %s", util.GetPathRelativeToWorkingDir(problem.File), problem.Line, problem.Column, problem.Message)
+	}
+}
+
+// ValidationProblems is the error returned when problems are found in the configuration files
+type ValidationProblems []ValidationProblem
+
+func (problems ValidationProblems) Error() string {
+	lines := make([]string, len(problems))
+	for i := range problems {
+		lines[i] = problems[i].String()
+	}
+	return fmt.Sprintf("%d problem(s) found in the configuration:\n  %s", len(problems), strings.Join(lines, "\n  "))
+}
+
+// The deprecated names that are still supported in the terragrunt block
+var deprecatedConfigKeys = map[string]string{"pre_hooks": "pre_hook", "post_hooks": "post_hook"}
+
+// A string value found in a configuration file
+type configLiteral struct {
+	include IncludeConfig
+	keyPath string
+	value   string
+	pos     token.Pos
+}
+
+type configValidator struct {
+	options    *options.TerragruntOptions
+	problems   ValidationProblems
+	literals   []configLiteral
+	defaults   map[string]interface{} // The default values of the terraform variables of the module
+	visited    map[string]bool
+	incomplete bool // Indicates that a file could not be read or parsed, so the whole configuration cannot be parsed
+}
+
+// ValidateConfigFile validates the configuration file of the options, the files it includes and the bootstrap files
+// without running terraform. Unlike ParseConfigFile, it does not stop on the first problem, all the problems found are
+// returned with their position. The parsed configuration is also returned if the configuration could be parsed.
+func ValidateConfigFile(terragruntOptions *options.TerragruntOptions) (config *TerragruntConfig, problems ValidationProblems) {
+	path, err := util.CanonicalPath(terragruntOptions.TerragruntConfigPath, "")
+	if err != nil {
+		return nil, ValidationProblems{{File: terragruntOptions.TerragruntConfigPath, Message: err.Error()}}
+	}
+
+	validator := &configValidator{options: terragruntOptions.Clone(path), visited: map[string]bool{}}
+	validator.options.IgnoreRemainingInterpolation = true
+	validator.options.StrictConfig = true
+	include := IncludeConfig{Path: path}
+	if !validator.validateFile(include) && !validator.incomplete {
+		validator.validateBootstrapFiles(include)
+	}
+
+	var parseErr error
+	if !validator.incomplete {
+		config, parseErr = ParseConfigFile(validator.options, include)
+	}
+
+	// The variables are now defined, so we can report the interpolations that cannot be resolved
+	validator.defaults, _ = util.LoadDefaultValues(filepath.Dir(path))
+	for _, literal := range validator.literals {
+		validator.checkLiteral(literal)
+	}
+
+	if parseErr != nil {
+		// The problems are only reported if they have not already been reported with their position
+		parseProblems, isProblems := errors.Unwrap(parseErr).(ValidationProblems)
+		if !isProblems {
+			parseProblems = ValidationProblems{{File: path, Message: fmt.Sprint(errors.Unwrap(parseErr))}}
+		}
+		for _, parseProblem := range parseProblems {
+			if !validator.problems.contains(parseProblem) {
+				validator.problems = append(validator.problems, parseProblem)
+			}
+		}
+	}
+	return config, validator.problems
+}
+
+// Indicates if the problem (or the same message without position) is already in the list
+func (problems ValidationProblems) contains(problem ValidationProblem) bool {
+	for _, existing := range problems {
+		if existing == problem || problem.Line == 0 && existing.Message == problem.Message {
+			return true
+		}
+	}
+	return false
+}
+
+func (validator *configValidator) addProblem(file string, pos token.Pos, format string, args ...interface{}) {
+	validator.problems = append(validator.problems, ValidationProblem{file, pos.Line, pos.Column, fmt.Sprintf(format, args...)})
+}
+
+// Validate the file and the files it includes. Returns true if the file has an include block (or if it cannot be
+// determined).
+func (validator *configValidator) validateFile(include IncludeConfig) bool {
+	path := include.Path
+	if validator.visited[path] {
+		return true
+	}
+	validator.visited[path] = true
+
+	content, err := util.ReadFileAsString(path)
+	if err != nil {
+		validator.addProblem(path, token.Pos{}, "%v", errors.Unwrap(err))
+		validator.incomplete = true
+		return true
+	}
+	if util.ApplyTemplate() && template.IsCode(content) {
+		validator.options.Logger.Debugf("%s contains go template code, only the resulting configuration is validated", path)
+		return true
+	}
+
+	list, problem := terragruntBlock(path, content)
+	if problem != nil {
+		validator.problems = append(validator.problems, *problem)
+		validator.incomplete = true
+		return true
+	}
+
+	// The problems related to the includes are reported by validateInclude
+	include.namedIncludes, _ = findNamedIncludes(content, include, validator.options)
+	validator.checkObject(include, list, reflect.TypeOf(TerragruntConfigFile{}), "")
+	return validator.validateInclude(include, list.Filter("include"))
+}
+
+// Returns the content of the terragrunt block of the configuration file or the problem that prevents reading it
+func terragruntBlock(path, content string) (*ast.ObjectList, *ValidationProblem) {
+	root, err := parser.Parse([]byte(content))
+	if err != nil {
+		if err, isPosError := err.(*parser.PosError); isPosError {
+			return nil, &ValidationProblem{path, err.Pos.Line, err.Pos.Column, err.Err.Error()}
+		}
+		return nil, &ValidationProblem{File: path, Message: err.Error()}
+	}
+
+	list, _ := root.Node.(*ast.ObjectList)
+	if !isOldTerragruntConfig(path) && list != nil {
+		terragrunt := list.Filter("terragrunt")
+		if len(terragrunt.Items) == 0 {
+			list = nil
+		} else if object, isObject := terragrunt.Items[0].Val.(*ast.ObjectType); isObject {
+			list = object.List
+		} else {
+			pos := terragrunt.Items[0].Val.Pos()
+			return nil, &ValidationProblem{path, pos.Line, pos.Column, "Invalid value for terragrunt, a block is expected"}
+		}
+	}
+	if list == nil {
+		return nil, &ValidationProblem{File: path, Message: CouldNotResolveTerragruntConfigInFile(path).Error()}
+	}
+	return list, nil
+}
+
+// Check the attributes and the values of the terragrunt block of the configuration. In strict mode, the problems are
+// returned as an error, otherwise, they are only logged as warnings. The deprecated .terragrunt files are never
+// checked strictly.
+func checkConfigSchema(configString string, include IncludeConfig, terragruntOptions *options.TerragruntOptions) error {
+	list, problem := terragruntBlock(include.Path, configString)
+	if problem != nil {
+		// The configuration cannot be read, the problem is reported when the configuration is decoded
+		return nil
+	}
+
+	validator := &configValidator{options: terragruntOptions}
+	validator.checkObject(include, list, reflect.TypeOf(TerragruntConfigFile{}), "")
+	if len(validator.problems) == 0 {
+		return nil
+	}
+	if terragruntOptions.StrictConfig && !isOldTerragruntConfig(include.Path) {
+		return errors.WithStackTrace(validator.problems)
+	}
+	terragruntOptions.Logger.Warning(validator.problems.Error())
+	return nil
+}
+
+// Validate the files included by the include blocks (if any)
+func (validator *configValidator) validateInclude(include IncludeConfig, includeBlocks *ast.ObjectList) bool {
+	for _, item := range includeBlocks.Items {
+		if len(item.Keys) == 0 && len(includeBlocks.Items) > 1 {
+			validator.addProblem(include.Path, item.Pos(), "%v", DuplicateInclude(""))
+			validator.incomplete = true
+			continue
+		}
+		validator.validateIncludeBlock(include, item)
+	}
+	return len(includeBlocks.Items) > 0
+}
+
+// Validate an include block and the file it includes
+func (validator *configValidator) validateIncludeBlock(include IncludeConfig, includeBlock *ast.ObjectItem) {
+	object, isObject := includeBlock.Val.(*ast.ObjectType)
+	if !isObject {
+		// The type problem has already been reported
+		validator.incomplete = true
+		return
+	}
+
+	included := IncludeConfig{isIncludedBy: &include}
+	var pos token.Pos
+	for _, item := range object.List.Items {
+		if strategies, isObject := item.Val.(*ast.ObjectType); isObject && keyValue(item.Keys[0]) == "merge_strategies" {
+			for _, strategy := range strategies.List.Items {
+				validator.checkMergeStrategy(include, keyValue(strategy.Keys[0]), strategy)
+			}
+			continue
+		}
+		literal, isLiteral := item.Val.(*ast.LiteralType)
+		if !isLiteral {
+			continue
+		}
+		switch keyValue(item.Keys[0]) {
+		case "path":
+			included.Path, pos = fmt.Sprint(literal.Token.Value()), literal.Pos()
+		case "source":
+			included.Source = fmt.Sprint(literal.Token.Value())
+		case "merge_strategy":
+			validator.checkMergeStrategy(include, "", item)
+		}
+	}
+
+	if included.Path == "" && included.Source == "" {
+		validator.addProblem(include.Path, includeBlock.Pos(), "%v", IncludedConfigMissingPath(include.Path))
+		validator.incomplete = true
+		return
+	}
+
+	var err error
+	if included.Path, err = ResolveTerragruntConfigString(included.Path, included, validator.options); err == nil {
+		included.Source, err = ResolveTerragruntConfigString(included.Source, included, validator.options)
+	}
+	if err != nil {
+		validator.addProblem(include.Path, includeBlock.Pos(), "%v", errors.Unwrap(err))
+		validator.incomplete = true
+		return
+	}
+
+	if included.Source != "" {
+		validator.options.Logger.Debugf("The included file %s from %s is not validated", included.Path, included.Source)
+		return
+	}
+
+	if !filepath.IsAbs(included.Path) {
+		included.Path = util.JoinPath(filepath.Dir(include.Path), included.Path)
+	}
+	if !util.FileExists(included.Path) {
+		validator.addProblem(include.Path, pos, "Included file %s not found", included.Path)
+		validator.incomplete = true
+		return
+	}
+	validator.validateFile(included)
+}
+
+// Validate the merge strategy of a section of the include (or the global merge_strategy if the section is empty)
+func (validator *configValidator) checkMergeStrategy(include IncludeConfig, section string, item *ast.ObjectItem) {
+	literal, isLiteral := item.Val.(*ast.LiteralType)
+	if !isLiteral {
+		// The type problem has already been reported
+		return
+	}
+	if err := validateMergeStrategy(section, fmt.Sprint(literal.Token.Value())); err != nil {
+		pos := literal.Pos()
+		if _, unknownSection := err.(UnknownMergeSection); unknownSection {
+			pos = item.Keys[0].Pos()
+		}
+		validator.addProblem(include.Path, pos, "%v", err)
+	}
+}
+
+// Validate the local bootstrap files defined by TERRAGRUNT_BOOT_CONFIGS
+func (validator *configValidator) validateBootstrapFiles(include IncludeConfig) {
+	for _, bootstrapFile := range strings.Split(os.Getenv(options.EnvBootConfigs), string(os.PathListSeparator)) {
+		bootstrapFile = strings.TrimSpace(bootstrapFile)
+		if stat, _ := os.Stat(bootstrapFile); bootstrapFile == "" || stat == nil || stat.IsDir() {
+			// The folders and remote sources are not validated
+			continue
+		}
+		bootstrapFile, _ = util.CanonicalPath(bootstrapFile, "")
+		validator.validateFile(IncludeConfig{Path: bootstrapFile, isBootstrap: true, isIncludedBy: &include})
+	}
+}
+
+// Check that the keys of the object are supported by the type and validate their values. The labels of the blocks
+// (i.e. the names of the extensions) must be unique within the object.
+func (validator *configValidator) checkObject(include IncludeConfig, list *ast.ObjectList, objectType reflect.Type, keyPath string) {
+	fields := configFields(objectType)
+	labels := map[string]token.Pos{}
+
+	for _, item := range list.Items {
+		key := keyValue(item.Keys[0])
+		if newKey, isDeprecated := deprecatedConfigKeys[key]; isDeprecated && keyPath == "" {
+			key = newKey
+		}
+		fieldType, known := fields[key]
+		if !known {
+			validator.addProblem(include.Path, item.Keys[0].Pos(), "Unknown attribute %s", joinKeyPath(keyPath, key))
+			continue
+		}
+
+		if len(item.Keys) > 1 {
+			label := keyValue(item.Keys[1])
+			if first, duplicated := labels[key+" "+label]; duplicated {
+				validator.addProblem(include.Path, item.Keys[1].Pos(), "Duplicate %s %q (already defined at line %d)", key, label, first.Line)
+			} else {
+				labels[key+" "+label] = item.Keys[1].Pos()
+			}
+		}
+
+		if keyPath == "" && key == "assume_role" {
+			validator.checkAssumeRole(include, item.Val)
+		}
+		validator.checkValue(include, item.Val, fieldType, joinKeyPath(keyPath, key))
+	}
+}
+
+// Validate the value according to the type of the field
+func (validator *configValidator) checkValue(include IncludeConfig, node ast.Node, fieldType reflect.Type, keyPath string) {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	if expected := expectedValue(node, fieldType); expected != "" {
+		validator.addProblem(include.Path, node.Pos(), "Invalid value for %s, %s is expected", keyPath, expected)
+		return
+	}
+
+	switch fieldType.Kind() {
+	case reflect.Struct:
+		switch node := node.(type) {
+		case *ast.ObjectType:
+			validator.checkObject(include, node.List, fieldType, keyPath)
+		case *ast.ListType:
+			for _, item := range node.List {
+				validator.checkValue(include, item, fieldType, keyPath)
+			}
+		}
+	case reflect.Slice:
+		if list, isList := node.(*ast.ListType); isList {
+			for _, item := range list.List {
+				validator.checkValue(include, item, fieldType.Elem(), keyPath)
+			}
+		} else {
+			validator.checkValue(include, node, fieldType.Elem(), keyPath)
+		}
+	default:
+		ast.Walk(node, func(node ast.Node) (ast.Node, bool) {
+			if literal, isLiteral := node.(*ast.LiteralType); isLiteral {
+				if value, isString := literal.Token.Value().(string); isString {
+					validator.literals = append(validator.literals, configLiteral{include, keyPath, value, literal.Pos()})
+				}
+			}
+			return node, true
+		})
+	}
+}
+
+// Returns the kind of value expected if the node cannot be decoded in a field of the type (or an empty string if the
+// value is valid). The strings containing interpolations are accepted for any scalar since they are resolved before
+// the configuration is decoded.
+func expectedValue(node ast.Node, fieldType reflect.Type) string {
+	literal, isLiteral := node.(*ast.LiteralType)
+	switch fieldType.Kind() {
+	case reflect.Interface:
+		return ""
+	case reflect.Struct:
+		if isLiteral {
+			return "a block"
+		}
+		return ""
+	case reflect.Map:
+		if isLiteral {
+			return "a map"
+		}
+		return ""
+	case reflect.Slice:
+		if isLiteral {
+			return "a list"
+		}
+		return ""
+	}
+
+	var expected, text string
+	var valid bool
+	if isLiteral {
+		text, _ = literal.Token.Value().(string)
+	}
+	switch fieldType.Kind() {
+	case reflect.String:
+		expected = "a string"
+		valid = isLiteral && literal.Token.Type != token.BOOL
+	case reflect.Bool:
+		expected = "a boolean"
+		_, err := strconv.ParseBool(text)
+		valid = isLiteral && (literal.Token.Type == token.BOOL || err == nil)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		expected = "a number"
+		_, err := strconv.ParseInt(text, 0, 64)
+		valid = isLiteral && (literal.Token.Type == token.NUMBER || err == nil)
+	default:
+		return ""
+	}
+
+	if valid || isLiteral && strings.Contains(text, "${") {
+		return ""
+	}
+	return expected
+}
+
+// Check that assume_role is either a string or a list of strings
+func (validator *configValidator) checkAssumeRole(include IncludeConfig, node ast.Node) {
+	isString := func(node ast.Node) bool {
+		literal, isLiteral := node.(*ast.LiteralType)
+		return isLiteral && (literal.Token.Type == token.STRING || literal.Token.Type == token.HEREDOC)
+	}
+
+	valid := isString(node)
+	if list, isList := node.(*ast.ListType); isList {
+		valid = true
+		for _, item := range list.List {
+			valid = valid && isString(item)
+		}
+	}
+	if !valid {
+		validator.addProblem(include.Path, node.Pos(), "Invalid configuration for assume_role, must be either a string or a list of strings")
+	}
+}
+
+// Report the interpolations that cannot be resolved and the dependencies that do not refer to a terragrunt module
+func (validator *configValidator) checkLiteral(literal configLiteral) {
+	value, err := ResolveTerragruntConfigString(literal.value, literal.include, validator.options)
+	if err != nil {
+		validator.addProblem(literal.include.Path, literal.pos, "%v", errors.Unwrap(err))
+		return
+	}
+
+	for _, match := range helperVarRegex.FindAllStringSubmatch(literal.value, -1) {
+		name := strings.Split(match[1], ".")[0]
+		if _, defined := validator.options.Variables[name]; !defined && validator.defaults[name] == nil && name != "dependency" {
+			validator.addProblem(literal.include.Path, literal.pos, "Variable %s is not defined", strings.TrimSpace(match[0]))
+		}
+	}
+
+	switch literal.keyPath {
+	case "dependencies.paths", "dependency.path":
+		if strings.Contains(value, "${") {
+			return
+		}
+		path, err := util.CanonicalPath(value, filepath.Dir(literal.include.Path))
+		if err == nil && !util.FileExists(DefaultConfigPath(path)) {
+			validator.addProblem(literal.include.Path, literal.pos, "Dependency %s is not a terragrunt module, there is no configuration in %s", literal.value, path)
+		}
+	}
+}
+
+// Returns the keys supported by the type (the hcl tag of the exported fields, the fields without tag are not read from
+// the configuration files)
+func configFields(objectType reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, objectType.NumField())
+	for i := 0; i < objectType.NumField(); i++ {
+		field := objectType.Field(i)
+		tag := strings.Split(field.Tag.Get("hcl"), ",")
+		switch {
+		case field.PkgPath != "":
+			// Unexported field
+		case util.ListContainsElement(tag[1:], "squash"):
+			for key, fieldType := range configFields(field.Type) {
+				fields[key] = fieldType
+			}
+		case util.ListContainsElement(tag[1:], "key"):
+			// The block label is not an attribute
+		case tag[0] != "":
+			fields[tag[0]] = field.Type
+		}
+	}
+	return fields
+}
+
+func keyValue(key *ast.ObjectKey) string {
+	return fmt.Sprint(key.Token.Value())
+}
+
+func joinKeyPath(keyPath, key string) string {
+	if keyPath == "" {
+		return key
+	}
+	return keyPath + "." + key
+}
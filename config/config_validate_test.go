@@ -0,0 +1,127 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfigFile(t *testing.T) {
+	t.Parallel()
+
+	fixture, _ := filepath.Abs("../test/fixture-validate-config/invalid")
+	child := filepath.Join(fixture, "child", DefaultTerragruntConfigPath)
+	parent := filepath.Join(fixture, DefaultTerragruntConfigPath)
+
+	_, problems := ValidateConfigFile(options.NewTerragruntOptionsForTest(child))
+	assert.Equal(t, ValidationProblems{
+		{child, 10, 17, "Invalid configuration for assume_role, must be either a string or a list of strings"},
+		{child, 17, 12, `Duplicate pre_hook "echo" (already defined at line 12)`},
+		{parent, 10, 3, "Unknown attribute pre_hoooks"},
+		{child, 7, 14, "Dependency ../missing is not a terragrunt module, there is no configuration in " + filepath.Join(fixture, "missing")},
+		{child, 14, 18, "Variable ${var.undefined} is not defined"},
+		{child, 18, 15, "Unknown helper function: unknown_function"},
+	}, problems)
+}
+
+func TestValidateConfigFileMissingInclude(t *testing.T) {
+	t.Parallel()
+
+	fixture, _ := filepath.Abs("../test/fixture-validate-config")
+	configPath := filepath.Join(fixture, "missing-include", DefaultTerragruntConfigPath)
+
+	config, problems := ValidateConfigFile(options.NewTerragruntOptionsForTest(configPath))
+	assert.Nil(t, config)
+	assert.Equal(t, ValidationProblems{
+		{configPath, 9, 5, "Unknown attribute terraform.extra_argument"},
+		{configPath, 3, 12, "Included file " + filepath.Join(fixture, "not-found", DefaultTerragruntConfigPath) + " not found"},
+	}, problems)
+}
+
+func TestValidationProblemString(t *testing.T) {
+	t.Parallel()
+
+	// The file is expressed relatively to the working directory
+	configPath, _ := filepath.Abs(DefaultTerragruntConfigPath)
+
+	assert.Equal(t, "Dependency cycle", ValidationProblem{Message: "Dependency cycle"}.String())
+	assert.Equal(t, "terraform.tfvars: Invalid", ValidationProblem{File: configPath, Message: "Invalid"}.String())
+	assert.Equal(t, "terraform.tfvars:3:5: Invalid", ValidationProblem{configPath, 3, 5, "Invalid"}.String())
+}
+
+func TestCheckConfigSchema(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terragrunt = {
+  pre_hoooks "a" {
+    command = "echo"
+  }
+
+  import_file "b" {}
+
+  terraform {
+    source = true
+  }
+
+  timeout = ["30m"]
+  priority = "high"
+  weight = "${var.weight}"
+
+  pre_hook "c" {
+    expand_args = "yes"
+    arguments = "single"
+  }
+
+  post_hooks "d" {
+    command = "echo"
+  }
+}
+`
+
+	configPath := "/test/check-config-schema/" + DefaultTerragruntConfigPath
+	terragruntOptions := options.NewTerragruntOptionsForTest(configPath)
+	terragruntOptions.StrictConfig = true
+	err := checkConfigSchema(config, IncludeConfig{Path: configPath}, terragruntOptions)
+	assert.Equal(t, ValidationProblems{
+		{configPath, 3, 3, "Unknown attribute pre_hoooks"},
+		{configPath, 7, 3, "Unknown attribute import_file"},
+		{configPath, 10, 14, "Invalid value for terraform.source, a string is expected"},
+		{configPath, 13, 13, "Invalid value for timeout, a string is expected"},
+		{configPath, 14, 14, "Invalid value for priority, a number is expected"},
+		{configPath, 18, 19, "Invalid value for pre_hook.expand_args, a boolean is expected"},
+		{configPath, 19, 17, "Invalid value for pre_hook.arguments, a list is expected"},
+	}, errors.Unwrap(err))
+
+	// The problems are only logged if the strict mode is disabled
+	terragruntOptions.StrictConfig = false
+	assert.Nil(t, checkConfigSchema(config, IncludeConfig{Path: configPath}, terragruntOptions))
+}
+
+func TestParseTerragruntConfigInvalidAssumeRole(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terragrunt = {
+  assume_role = 12
+}
+`
+
+	terragruntOptions := mockOptions.Clone(mockOptions.TerragruntConfigPath)
+	terragruntOptions.StrictConfig = true
+	_, err := parseConfigString(config, terragruntOptions, mockDefaultInclude)
+	assert.IsType(t, InvalidAssumeRole{}, errors.Unwrap(err))
+}
+
+func TestConfigFieldsIgnoreUntaggedFields(t *testing.T) {
+	t.Parallel()
+
+	fields := configFields(reflect.TypeOf(TerragruntConfigFile{}))
+	assert.Contains(t, fields, "include")
+	assert.Contains(t, fields, "pre_hook")
+	assert.NotContains(t, fields, "path")
+}
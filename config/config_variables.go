@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// ImportVariables represents a source of variables that are loaded into the terragrunt variables when the configuration
+// file is parsed
+type ImportVariables struct {
+	SSMPath string `hcl:"ssm_path"` // The path of the parameters hierarchy in the AWS SSM parameter store
+	Region  string `hcl:"region"`   // The region of the parameter store (the default region if not specified)
+}
+
+// ImportVariablesList represents the list of import_variables blocks of a configuration file
+type ImportVariablesList []ImportVariables
+
+// Import loads the variables of all import_variables blocks into the terragrunt options
+func (list ImportVariablesList) Import(terragruntOptions *options.TerragruntOptions) error {
+	for _, item := range list {
+		if item.SSMPath == "" {
+			return errors.WithStackTrace(MissingSSMPath(terragruntOptions.TerragruntConfigPath))
+		}
+		if _, err := DiscoverAll(item.SSMPath, item.Region, terragruntOptions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DiscoverAll loads all the parameters under the path from the AWS SSM parameter store as terragrunt variables. The
+// hierarchy of the parameters is converted into nested maps (i.e. /path/db/password => db.password) and returned.
+func DiscoverAll(path, region string, terragruntOptions *options.TerragruntOptions) (map[string]interface{}, error) {
+	// The parameters could contain secrets, so they are only kept in memory
+	cacheKey, _ := awsCacheKey(terragruntOptions, "discover_all", path, region)
+	parameters, err := terragruntOptions.Cache.Memoize(cacheKey, false, func() (interface{}, error) {
+		return aws_helper.GetSSMParametersByPath(path, region)
+	})
+	if err != nil {
+		return nil, errors.WithStackTraceAndPrefix(err, "Unable to get the parameters under %s (region=%s)", path, region)
+	}
+
+	variables := parametersHierarchy(path, parameters.([]*ssm.Parameter), terragruntOptions)
+	for key, value := range variables {
+		terragruntOptions.SetVariable(key, value, options.ParameterStore)
+	}
+	return variables, nil
+}
+
+// Converts the parameters into nested maps according to their names relative to the path. A parameter that is also the
+// parent of other parameters is ignored. The decrypted values of the secure strings are considered as secrets, so they
+// are redacted from the logs.
+func parametersHierarchy(path string, parameters []*ssm.Parameter, terragruntOptions *options.TerragruntOptions) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, parameter := range parameters {
+		if parameter.Name == nil || parameter.Value == nil {
+			continue
+		}
+		name, value := *parameter.Name, *parameter.Value
+		if parameter.Type != nil && *parameter.Type == ssm.ParameterTypeSecureString {
+			terragruntOptions.Cache.AddSecret(value)
+		}
+
+		keys := strings.Split(strings.Trim(strings.TrimPrefix(name, path), "/"), "/")
+		if keys[0] == "" {
+			// The parameter is the path itself, it has no name in the hierarchy
+			terragruntOptions.Logger.Warningf("Parameter %s ignored since it is not under %s", name, path)
+			continue
+		}
+
+		current := result
+		for i, key := range keys[:len(keys)-1] {
+			child, isMap := current[key].(map[string]interface{})
+			if !isMap {
+				if current[key] != nil {
+					parent := strings.TrimRight(path, "/") + "/" + strings.Join(keys[:i+1], "/")
+					terragruntOptions.Logger.Warningf("Parameter %s ignored since it is also the parent of other parameters", parent)
+				}
+				child = map[string]interface{}{}
+				current[key] = child
+			}
+			current = child
+		}
+
+		key := keys[len(keys)-1]
+		if _, isMap := current[key].(map[string]interface{}); isMap {
+			terragruntOptions.Logger.Warningf("Parameter %s ignored since it is also the parent of other parameters", name)
+			continue
+		}
+		current[key] = value
+	}
+	return result
+}
+
+// MissingSSMPath is the error returned when an import_variables block does not specify the path of the parameters
+type MissingSSMPath string
+
+func (err MissingSSMPath) Error() string {
+	return fmt.Sprintf("The import_variables block of %s must define ssm_path", string(err))
+}
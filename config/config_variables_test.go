@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParametersHierarchy(t *testing.T) {
+	t.Parallel()
+
+	parameter := func(name, value, parameterType string) *ssm.Parameter {
+		return &ssm.Parameter{Name: aws.String(name), Value: aws.String(value), Type: aws.String(parameterType)}
+	}
+	parameters := []*ssm.Parameter{
+		parameter("/app/prod/name", "my-app", ssm.ParameterTypeString),
+		parameter("/app/prod/db/host", "db.example.com", ssm.ParameterTypeString),
+		parameter("/app/prod/db/password", "parameter-store-password", ssm.ParameterTypeSecureString),
+		parameter("/app/prod/network", "ignored", ssm.ParameterTypeString),
+		parameter("/app/prod/network/subnets/private", "10.0.0.0/24,10.0.1.0/24", ssm.ParameterTypeStringList),
+		parameter("/app/prod/network/vpc", "vpc-1234", ssm.ParameterTypeString),
+	}
+
+	expected := map[string]interface{}{
+		"name": "my-app",
+		"db": map[string]interface{}{
+			"host":     "db.example.com",
+			"password": "parameter-store-password",
+		},
+		"network": map[string]interface{}{
+			"subnets": map[string]interface{}{"private": "10.0.0.0/24,10.0.1.0/24"},
+			"vpc":     "vpc-1234",
+		},
+	}
+	assert.Equal(t, expected, parametersHierarchy("/app/prod/", parameters, mockOptions))
+
+	// The secure strings are redacted from the logs
+	assert.Equal(t, "password=***", mockOptions.Cache.RedactSecrets("password=parameter-store-password"))
+}
+
+func TestImportVariablesWithoutPath(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest(DefaultTerragruntConfigPath)
+	_, err := parseConfigString("terragrunt = { import_variables {} }", terragruntOptions, IncludeConfig{Path: DefaultTerragruntConfigPath})
+	assert.Equal(t, MissingSSMPath(DefaultTerragruntConfigPath), errors.Unwrap(err))
+}
+
+func TestHclLiteral(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{"value", `"value"`},
+		{10, "10"},
+		{[]interface{}{"a", 1}, `["a", 1]`},
+		{map[string]interface{}{"b": "2", "a": map[string]interface{}{"c": true}}, `{ "a" = { "c" = true }, "b" = "2" }`},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, hclLiteral(testCase.value))
+	}
+}
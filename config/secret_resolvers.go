@@ -0,0 +1,326 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coveo/gotemplate/hcl"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// SecretReference represents a reference to a secret value (i.e. vault://secret/app#password)
+type SecretReference struct {
+	Scheme  string     // The resolver used to get the secret (vault, file, env, ssm, secretsmanager)
+	Path    string     // The location of the secret (specific to the resolver)
+	Key     string     // The optional key to extract from the secret if it is a structured document (json, yaml or hcl)
+	Options url.Values // The optional query parameters (i.e. ?region=us-east-1)
+}
+
+func (reference SecretReference) String() string {
+	result := fmt.Sprintf("%s://%s", reference.Scheme, reference.Path)
+	if len(reference.Options) > 0 {
+		result += "?" + reference.Options.Encode()
+	}
+	if reference.Key != "" {
+		result += "#" + reference.Key
+	}
+	return result
+}
+
+// SecretResolver is the interface that must be implemented to resolve the secrets of a scheme. The resolver returns
+// the raw value of the secret, the key of the reference is extracted afterward.
+type SecretResolver interface {
+	Resolve(reference SecretReference, terragruntOptions *options.TerragruntOptions) (string, error)
+}
+
+// SecretResolverFunc allows using a simple function as SecretResolver
+type SecretResolverFunc func(SecretReference, *options.TerragruntOptions) (string, error)
+
+// Resolve calls the function
+func (resolver SecretResolverFunc) Resolve(reference SecretReference, terragruntOptions *options.TerragruntOptions) (string, error) {
+	return resolver(reference, terragruntOptions)
+}
+
+var secretResolvers = map[string]SecretResolver{
+	"env":            SecretResolverFunc(resolveEnvSecret),
+	"file":           SecretResolverFunc(resolveFileSecret),
+	"ssm":            SecretResolverFunc(resolveSSMSecret),
+	"secretsmanager": SecretResolverFunc(resolveSecretsManagerSecret),
+	"vault":          SecretResolverFunc(resolveVaultSecret),
+}
+
+// Protects the access to the secret resolvers
+var secretResolversMutex sync.Mutex
+
+// RegisterSecretResolver adds (or replaces) the resolver used for a scheme
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolversMutex.Lock()
+	defer secretResolversMutex.Unlock()
+	secretResolvers[scheme] = resolver
+}
+
+// ParseSecretReference converts a string of the form scheme://path[?options][#key] into a secret reference
+func ParseSecretReference(reference string) (result SecretReference, err error) {
+	parts := strings.SplitN(reference, "://", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return result, InvalidSecretReference(reference)
+	}
+	result.Scheme, result.Path = parts[0], parts[1]
+
+	if index := strings.LastIndex(result.Path, "#"); index >= 0 {
+		result.Path, result.Key = result.Path[:index], result.Path[index+1:]
+	}
+	if index := strings.Index(result.Path, "?"); index >= 0 {
+		if result.Options, err = url.ParseQuery(result.Path[index+1:]); err != nil {
+			return result, InvalidSecretReference(reference)
+		}
+		result.Path = result.Path[:index]
+	}
+	return
+}
+
+// ResolveSecret returns the value of the secret reference. The values are cached for the whole run (so a secret is
+// only fetched once) and they are redacted from the logs.
+func ResolveSecret(reference string, terragruntOptions *options.TerragruntOptions) (string, error) {
+	parsed, err := ParseSecretReference(reference)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	if parsed.Scheme == "file" && !filepath.IsAbs(parsed.Path) {
+		// The relative paths are made absolute to avoid sharing the cached value between modules in different folders
+		if parsed.Path, err = filepath.Abs(filepath.Join(filepath.Dir(terragruntOptions.TerragruntConfigPath), parsed.Path)); err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+	}
+
+	secretResolversMutex.Lock()
+	resolver := secretResolvers[parsed.Scheme]
+	secretResolversMutex.Unlock()
+	if resolver == nil {
+		return "", errors.WithStackTrace(UnknownSecretScheme(parsed.Scheme))
+	}
+
+	// The secrets are never saved on disk
+	value, err := terragruntOptions.Cache.Memoize(secretCacheKey(parsed, terragruntOptions), false, func() (interface{}, error) {
+		value, err := resolver.Resolve(parsed, terragruntOptions)
+		if err != nil {
+			return nil, errors.WithStackTraceAndPrefix(err, "Unable to resolve secret %s", parsed)
+		}
+		if parsed.Key != "" {
+			if value, err = extractSecretKey(value, parsed.Key); err != nil {
+				return nil, errors.WithStackTraceAndPrefix(err, "Unable to resolve secret %s", parsed)
+			}
+		}
+		terragruntOptions.Cache.AddSecret(value)
+		return value, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// Returns the key used to cache the value of a secret during the run. The environment variables could be different for
+// each module, so the module is part of the key of the env secrets.
+func secretCacheKey(reference SecretReference, terragruntOptions *options.TerragruntOptions) string {
+	key := "secret " + reference.String()
+	if reference.Scheme == "env" {
+		key += "@" + terragruntOptions.TerragruntConfigPath
+	}
+	return key
+}
+
+// Returns the value of the key (dot separated for nested objects) in the secret document (json, yaml or hcl)
+func extractSecretKey(content, key string) (string, error) {
+	var document map[string]interface{}
+	if yaml.Unmarshal([]byte(content), &document) != nil || document == nil {
+		document = nil
+		if err := hcl.Unmarshal([]byte(content), &document); err != nil {
+			return "", fmt.Errorf("The secret is not a json, yaml or hcl document, the key %s cannot be extracted", key)
+		}
+	}
+
+	var value interface{} = document
+	for _, part := range strings.Split(key, ".") {
+		value = mapValue(value, part)
+		if value == nil {
+			return "", SecretKeyNotFound(key)
+		}
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+		result, err := json.Marshal(jsonValue(value))
+		return string(result), err
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
+
+func mapValue(value interface{}, key string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return value[key]
+	case map[interface{}]interface{}:
+		return value[key]
+	case []map[string]interface{}:
+		// HCL blocks are decoded as list of objects
+		if len(value) == 1 {
+			return value[0][key]
+		}
+	}
+	return nil
+}
+
+// Converts the maps decoded from yaml (with interface{} keys) into maps that could be marshalled in json
+func jsonValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[fmt.Sprint(key)] = jsonValue(item)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[key] = jsonValue(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i := range value {
+			result[i] = jsonValue(value[i])
+		}
+		return result
+	}
+	return value
+}
+
+// env://NAME returns the value of the environment variable
+func resolveEnvSecret(reference SecretReference, terragruntOptions *options.TerragruntOptions) (string, error) {
+	if value, exist := terragruntOptions.Env[reference.Path]; exist {
+		return value, nil
+	}
+	if value, exist := os.LookupEnv(reference.Path); exist {
+		return value, nil
+	}
+	return "", fmt.Errorf("Environment variable %s is not defined", reference.Path)
+}
+
+// file://path returns the content of the file (relative paths are relative to the folder of the configuration file, the
+// secret() function makes them relative to the file being processed)
+func resolveFileSecret(reference SecretReference, terragruntOptions *options.TerragruntOptions) (string, error) {
+	path := reference.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(terragruntOptions.TerragruntConfigPath), path)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// ssm://name?region=region returns the decrypted value of the SSM parameter
+func resolveSSMSecret(reference SecretReference, terragruntOptions *options.TerragruntOptions) (string, error) {
+	return aws_helper.GetSSMParameter(reference.Path, reference.Options.Get("region"))
+}
+
+// secretsmanager://name?region=region returns the current value of the secret stored in AWS Secrets Manager
+func resolveSecretsManagerSecret(reference SecretReference, terragruntOptions *options.TerragruntOptions) (string, error) {
+	return aws_helper.GetSecretValue(reference.Path, reference.Options.Get("region"))
+}
+
+// vault://path returns the data of the Vault secret as a json document. The Vault server and the token are read from
+// the standard VAULT_ADDR and VAULT_TOKEN environment variables (or the ~/.vault-token file).
+func resolveVaultSecret(reference SecretReference, terragruntOptions *options.TerragruntOptions) (string, error) {
+	address := os.Getenv("VAULT_ADDR")
+	if address == "" {
+		address = "https://127.0.0.1:8200"
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		content, _ := ioutil.ReadFile(filepath.Join(os.Getenv("HOME"), ".vault-token"))
+		token = strings.TrimSpace(string(content))
+	}
+
+	request, err := http.NewRequest("GET", fmt.Sprintf("%s/v1/%s", strings.TrimRight(address, "/"), strings.TrimLeft(reference.Path, "/")), nil)
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		request.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	response, err := (&http.Client{Timeout: 30 * time.Second}).Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Vault returned %s", response.Status)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", err
+	}
+	data := secret.Data
+	if nested, isMap := data["data"].(map[string]interface{}); isMap && data["metadata"] != nil {
+		// The secrets of the KV version 2 engine are nested in a data object
+		data = nested
+	}
+	result, err := json.Marshal(data)
+	return string(result), err
+}
+
+// InvalidSecretReference is the error returned when a secret reference is not of the form scheme://path[#key]
+type InvalidSecretReference string
+
+func (err InvalidSecretReference) Error() string {
+	return fmt.Sprintf("Invalid secret reference %q, it must be of the form scheme://path[#key]", string(err))
+}
+
+// UnknownSecretScheme is the error returned when there is no resolver registered for the scheme of a secret reference
+type UnknownSecretScheme string
+
+func (err UnknownSecretScheme) Error() string {
+	secretResolversMutex.Lock()
+	schemes := make([]string, 0, len(secretResolvers))
+	for scheme := range secretResolvers {
+		schemes = append(schemes, scheme)
+	}
+	secretResolversMutex.Unlock()
+	sort.Strings(schemes)
+	return fmt.Sprintf("Unknown secret scheme %s, must be one of %s", string(err), strings.Join(schemes, ", "))
+}
+
+// SecretKeyNotFound is the error returned when the key of a secret reference is not found in the secret
+type SecretKeyNotFound string
+
+func (err SecretKeyNotFound) Error() string {
+	return fmt.Sprintf("Key %s not found in the secret", string(err))
+}
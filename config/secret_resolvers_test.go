@@ -0,0 +1,147 @@
+package config
+
+import (
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSecretReference(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		reference string
+		expected  SecretReference
+		err       error
+	}{
+		{"env://PASSWORD", SecretReference{Scheme: "env", Path: "PASSWORD"}, nil},
+		{"vault://secret/data/app#password", SecretReference{Scheme: "vault", Path: "secret/data/app", Key: "password"}, nil},
+		{"ssm:///app/password?region=us-east-1", SecretReference{Scheme: "ssm", Path: "/app/password", Options: url.Values{"region": {"us-east-1"}}}, nil},
+		{"file://../secrets.json#database.user", SecretReference{Scheme: "file", Path: "../secrets.json", Key: "database.user"}, nil},
+		{"password", SecretReference{}, InvalidSecretReference("password")},
+		{"env://", SecretReference{}, InvalidSecretReference("env://")},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := ParseSecretReference(testCase.reference)
+		assert.Equal(t, testCase.err, err, testCase.reference)
+		if err == nil {
+			assert.Equal(t, testCase.expected, actual, testCase.reference)
+			assert.Equal(t, testCase.reference, actual.String())
+		}
+	}
+}
+
+func TestResolveSecret(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-secrets/" + DefaultTerragruntConfigPath)
+	terragruntOptions.Env["TEST_RESOLVE_SECRET"] = "env-password"
+
+	testCases := []struct {
+		reference string
+		expected  string
+	}{
+		{"env://TEST_RESOLVE_SECRET", "env-password"},
+		{"file://password.txt", "file-password"},
+		{"file://secrets.json#database.password", "json-password"},
+		{"file://secrets.json#database", `{"password":"json-password","user":"admin"}`},
+		{"file://secrets.yml#api_key", "yaml-api-key"},
+		{"file://secrets.tfvars#token", "hcl-token"},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := ResolveSecret(testCase.reference, terragruntOptions)
+		assert.NoError(t, err, testCase.reference)
+		assert.Equal(t, testCase.expected, actual, testCase.reference)
+	}
+}
+
+func TestResolveSecretErrors(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-secrets/" + DefaultTerragruntConfigPath)
+
+	_, err := ResolveSecret("unknown://secret", terragruntOptions)
+	assert.IsType(t, UnknownSecretScheme(""), errors.Unwrap(err))
+
+	_, err = ResolveSecret("file://secrets.json#database.missing", terragruntOptions)
+	assert.Contains(t, err.Error(), SecretKeyNotFound("database.missing").Error())
+
+	_, err = ResolveSecret("env://TEST_UNDEFINED_SECRET_VARIABLE", terragruntOptions)
+	assert.Error(t, err)
+}
+
+func TestSecretCacheAndRedaction(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	RegisterSecretResolver("test-cache", SecretResolverFunc(func(reference SecretReference, _ *options.TerragruntOptions) (string, error) {
+		calls++
+		return "cached-" + reference.Path, nil
+	}))
+
+	for i := 0; i < 2; i++ {
+		value, err := ResolveSecret("test-cache://value", mockOptions)
+		assert.NoError(t, err)
+		assert.Equal(t, "cached-value", value)
+	}
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "password = ***", mockOptions.Cache.RedactSecrets("password = cached-value"))
+}
+
+func TestSecretCacheWithModulesInDifferentFolders(t *testing.T) {
+	t.Parallel()
+
+	// The modules share the cache of the run
+	moduleA := options.NewTerragruntOptionsForTest("../test/fixture-secrets/module-a/" + DefaultTerragruntConfigPath)
+	moduleB := moduleA.Clone("../test/fixture-secrets/module-b/" + DefaultTerragruntConfigPath)
+	moduleA.Env["TEST_MODULE_SECRET"] = "env-module-a"
+	moduleB.Env["TEST_MODULE_SECRET"] = "env-module-b"
+
+	for _, testCase := range []struct {
+		terragruntOptions *options.TerragruntOptions
+		file, env         string
+	}{
+		{moduleA, "module-a-password", "env-module-a"},
+		{moduleB, "module-b-password", "env-module-b"},
+	} {
+		value, err := ResolveSecret("file://db.txt", testCase.terragruntOptions)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.file, value)
+
+		value, err = ResolveSecret("env://TEST_MODULE_SECRET", testCase.terragruntOptions)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.env, value)
+	}
+}
+
+func TestSecretInIncludedFile(t *testing.T) {
+	t.Parallel()
+
+	// The relative path is relative to the included file that calls the function
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-secrets/module-a/" + DefaultTerragruntConfigPath)
+	parent, _ := filepath.Abs("../test/fixture-secrets/" + DefaultTerragruntConfigPath)
+	value, err := ResolveTerragruntConfigString(`${secret("file://password.txt")}`, IncludeConfig{Path: parent}, terragruntOptions)
+	assert.NoError(t, err)
+	assert.Equal(t, "file-password", value)
+}
+
+func TestSecretInterpolation(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("../test/fixture-secrets/" + DefaultTerragruntConfigPath)
+	config := `
+terragrunt = {
+  description = "${secret("file://secrets.json#database.user")}"
+}
+`
+	conf, err := parseConfigString(config, terragruntOptions, IncludeConfig{Path: terragruntOptions.TerragruntConfigPath})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "admin", conf.Description)
+	}
+}
@@ -0,0 +1,203 @@
+package configstack
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// Returns the list of files (absolute paths) that have been changed since the given git reference in the git
+// repository containing the working directory. This includes uncommitted and untracked files.
+func getChangedFiles(ref string, terragruntOptions *options.TerragruntOptions) ([]string, error) {
+	git := func(args ...string) ([]string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := shell.NewCmd(terragruntOptions, "git").Args(args...)
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, errors.WithStackTrace(GitCommandError{strings.Join(args, " "), strings.TrimSpace(stderr.String()), err})
+		}
+		return util.RemoveElementFromList(strings.Split(strings.TrimSpace(stdout.String()), "\n"), ""), nil
+	}
+
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	if len(root) != 1 {
+		return nil, errors.WithStackTrace(fmt.Errorf("Unable to determine the root of the git repository of %s", terragruntOptions.WorkingDir))
+	}
+
+	// The commands are run from the root of the repository to get the files outside of the working directory
+	changed, err := git("-C", root[0], "diff", "--name-only", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := git("-C", root[0], "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(changed)+len(untracked))
+	for _, file := range append(changed, untracked...) {
+		result = append(result, util.JoinPath(root[0], file))
+	}
+	return util.RemoveDuplicatesFromListKeepFirst(result), nil
+}
+
+// Restrict the stack to the modules affected by the changes made since the git reference specified in
+// --terragrunt-changed-since (and their dependents if --terragrunt-changed-dependents is specified)
+func (stack *Stack) filterChangedModules(terragruntOptions *options.TerragruntOptions) error {
+	changedFiles, err := getChangedFiles(terragruntOptions.ChangedSince, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	selected := findChangedModules(stack.Modules, changedFiles)
+	if terragruntOptions.ChangedWithDependents {
+		addDependents(stack.Modules, selected)
+	}
+
+	for _, module := range stack.Modules {
+		if !selected[module.Path] {
+			terragruntOptions.Logger.Debugf("Module %s is not affected by the changes since %s", util.GetPathRelativeToWorkingDirMax(module.Path, 3), terragruntOptions.ChangedSince)
+		}
+	}
+	stack.Modules = filterModules(stack.Modules, selected)
+	terragruntOptions.Logger.Noticef("%d module(s) affected by the changes since %s", len(stack.Modules), terragruntOptions.ChangedSince)
+	return nil
+}
+
+// Returns the set of modules that are affected by at least one of the changed files. A module is affected if a file
+// is changed in its folder (excluding nested modules), in one of its configuration files (including included and
+// bootstrap files), in a local source of its terraform or import_files configuration or in one of its dependencies.
+func findChangedModules(modules []*TerraformModule, changedFiles []string) map[string]bool {
+	// Returns the deepest module that contains the file
+	owner := func(file string) (result string) {
+		for _, module := range modules {
+			if isInFolder(file, module.Path) && len(module.Path) > len(result) {
+				result = module.Path
+			}
+		}
+		return
+	}
+
+	owners := map[string]bool{}
+	for _, file := range changedFiles {
+		if path := owner(file); path != "" {
+			owners[path] = true
+		}
+	}
+
+	selected := map[string]bool{}
+	for _, module := range modules {
+		if owners[module.Path] {
+			selected[module.Path] = true
+			continue
+		}
+
+		folders := module.Config.ImportFilesSources()
+		if source := localTerraformSource(module); source != "" {
+			folders = append(folders, source)
+		}
+		if module.Config.Dependencies != nil {
+			folders = append(folders, module.Config.Dependencies.Paths...)
+		}
+
+		for _, file := range changedFiles {
+			if util.ListContainsElement(module.Config.Files(), file) || isInAnyFolder(file, folders) {
+				selected[module.Path] = true
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// Returns true if the file is within the folder
+func isInFolder(file, folder string) bool {
+	return strings.HasPrefix(file, strings.TrimSuffix(folder, "/")+"/")
+}
+
+// Returns true if the file is within one of the folders
+func isInAnyFolder(file string, folders []string) bool {
+	for _, folder := range folders {
+		if isInFolder(file, folder) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the local folder referenced by the terraform source of the module (or an empty string if the source is
+// not a local folder)
+func localTerraformSource(module *TerraformModule) string {
+	if module.Config.Terraform == nil {
+		return ""
+	}
+	source := module.Config.Terraform.Source
+	if source == "" || strings.Contains(source, "::") || strings.Contains(source, "://") {
+		return ""
+	}
+	// The double slash indicates a sub folder within the source, the whole source folder is copied
+	source = strings.SplitN(source, "//", 2)[0]
+	source, err := util.CanonicalPath(source, module.Path)
+	if err != nil || !util.FileExists(source) {
+		return ""
+	}
+	return source
+}
+
+// Add all modules depending directly or indirectly on the selected modules to the selection
+func addDependents(modules []*TerraformModule, selected map[string]bool) {
+	for changed := true; changed; {
+		changed = false
+		for _, module := range modules {
+			if selected[module.Path] {
+				continue
+			}
+			for _, dependency := range module.Dependencies {
+				if selected[dependency.Path] {
+					selected[module.Path] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// Returns the list of selected modules. The dependencies to modules that are not selected are removed since they
+// are considered as already applied.
+func filterModules(modules []*TerraformModule, selected map[string]bool) []*TerraformModule {
+	result := make([]*TerraformModule, 0, len(selected))
+	for _, module := range modules {
+		if !selected[module.Path] {
+			continue
+		}
+		dependencies := make([]*TerraformModule, 0, len(module.Dependencies))
+		for _, dependency := range module.Dependencies {
+			if selected[dependency.Path] {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		module.Dependencies = dependencies
+		result = append(result, module)
+	}
+	return result
+}
+
+// GitCommandError is the error returned when a git command used to determine the changed files fails
+type GitCommandError struct {
+	Command string
+	Output  string
+	Err     error
+}
+
+func (err GitCommandError) Error() string {
+	return fmt.Sprintf("Error while running git %s: %v\n%s", err.Command, err.Err, err.Output)
+}
@@ -0,0 +1,149 @@
+package configstack
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindChangedModules(t *testing.T) {
+	t.Parallel()
+
+	moduleA := &TerraformModule{Path: "/stack/a", TerragruntOptions: mockOptions}
+	moduleB := &TerraformModule{Path: "/stack/a/b", TerragruntOptions: mockOptions}
+	moduleC := &TerraformModule{
+		Path:              "/stack/c",
+		Dependencies:      []*TerraformModule{moduleB},
+		Config:            config.TerragruntConfig{Dependencies: &config.ModuleDependencies{Paths: []string{"/stack/a/b"}}},
+		TerragruntOptions: mockOptions,
+	}
+	moduleD := &TerraformModule{Path: "/stack/d", Dependencies: []*TerraformModule{moduleC}, TerragruntOptions: mockOptions}
+	moduleE := &TerraformModule{Path: "/stack/e", TerragruntOptions: mockOptions}
+	modules := []*TerraformModule{moduleA, moduleB, moduleC, moduleD, moduleE}
+
+	testCases := []struct {
+		changedFiles []string
+		dependents   bool
+		expected     []*TerraformModule
+	}{
+		{nil, false, []*TerraformModule{}},
+		{[]string{"/stack/a/main.tf"}, false, []*TerraformModule{moduleA}},
+		{[]string{"/stack/a/main.tf"}, true, []*TerraformModule{moduleA}},
+		{[]string{"/stack/a/b/main.tf"}, false, []*TerraformModule{moduleB, moduleC}},
+		{[]string{"/stack/a/b/main.tf"}, true, []*TerraformModule{moduleB, moduleC, moduleD}},
+		{[]string{"/stack/e/main.tf", "/other/main.tf"}, true, []*TerraformModule{moduleE}},
+		{[]string{"/stack/ee/main.tf"}, true, []*TerraformModule{}},
+	}
+
+	for _, testCase := range testCases {
+		selected := findChangedModules(modules, testCase.changedFiles)
+		if testCase.dependents {
+			addDependents(modules, selected)
+		}
+
+		var actual []*TerraformModule
+		for _, module := range modules {
+			if selected[module.Path] {
+				actual = append(actual, module)
+			}
+		}
+		assertModuleListsEqual(t, testCase.expected, actual, "For changes %v (dependents = %v)", testCase.changedFiles, testCase.dependents)
+	}
+}
+
+func TestFilterModulesRemovesUnselectedDependencies(t *testing.T) {
+	t.Parallel()
+
+	moduleA := &TerraformModule{Path: "a", TerragruntOptions: mockOptions}
+	moduleB := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{moduleA}, TerragruntOptions: mockOptions}
+	moduleC := &TerraformModule{Path: "c", Dependencies: []*TerraformModule{moduleA, moduleB}, TerragruntOptions: mockOptions}
+
+	actual := filterModules([]*TerraformModule{moduleA, moduleB, moduleC}, map[string]bool{"b": true, "c": true})
+
+	if assert.Len(t, actual, 2) {
+		assert.Empty(t, actual[0].Dependencies)
+		assert.Equal(t, []*TerraformModule{moduleB}, actual[1].Dependencies)
+	}
+}
+
+func TestGetChangedFilesFromSubFolder(t *testing.T) {
+	t.Parallel()
+
+	root, err := ioutil.TempDir("", "changed-files")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(root)
+	// The temporary folder could be a symlink (i.e. on macOS), git returns the real path
+	if root, err = filepath.EvalSymlinks(root); !assert.NoError(t, err) {
+		return
+	}
+
+	write := func(file, content string) {
+		path := filepath.Join(root, file)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", root, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(output))
+	}
+
+	write("stack/a/main.tf", "")
+	write("stack/b/main.tf", "")
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	write("stack/b/main.tf", "# modified")
+	write("stack/b/untracked.tf", "")
+	write("other/untracked.tf", "")
+
+	// Terragrunt is launched from a sub folder of the repository
+	terragruntOptions := options.NewTerragruntOptionsForTest(filepath.Join(root, "stack", "a", "terraform.tfvars"))
+	changed, err := getChangedFiles("HEAD", terragruntOptions)
+	if assert.NoError(t, err) {
+		expected := []string{
+			util.JoinPath(root, "stack/b/main.tf"),
+			util.JoinPath(root, "other/untracked.tf"),
+			util.JoinPath(root, "stack/b/untracked.tf"),
+		}
+		assert.Equal(t, expected, changed)
+	}
+}
+
+func TestChangedSinceWithDependencyCycle(t *testing.T) {
+	t.Parallel()
+
+	// The folder is not a git repository, the cycle must be reported before git is called
+	root, err := ioutil.TempDir("", "changed-cycle")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(root)
+
+	var paths []string
+	for module, dependency := range map[string]string{"a": "../b", "b": "../a"} {
+		path := filepath.Join(root, module, config.DefaultTerragruntConfigPath)
+		content := fmt.Sprintf("terragrunt = { dependencies { paths = [%q] } }", dependency)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		paths = append(paths, path)
+	}
+
+	terragruntOptions := options.NewTerragruntOptionsForTest(filepath.Join(root, config.DefaultTerragruntConfigPath))
+	terragruntOptions.ChangedSince = "HEAD"
+	_, err = createStackForTerragruntConfigPaths(root, paths, terragruntOptions)
+	_, isCycle := errors.Unwrap(err).(DependencyCycle)
+	assert.True(t, isCycle, "Expected a dependency cycle but got %v", err)
+}
@@ -0,0 +1,127 @@
+package configstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// Records the modules that have been successfully completed during a run, this allows resuming a failed run
+type checkpoint struct {
+	RunID     string   `json:"run_id"`
+	Command   string   `json:"command"`
+	Succeeded []string `json:"succeeded"`
+	path      string
+}
+
+// The commands that modify the infrastructure, the other commands (i.e. plan, output) do not need to be resumed
+var checkpointCommands = []string{"apply", "destroy", "import", "refresh", "taint", "untaint"}
+
+// Returns the path of the checkpoint file associated to the run id
+func checkpointPath(runID string) string {
+	return util.GetTempDownloadFolder("terragrunt-cache", "checkpoints", runID+".json")
+}
+
+// Load the checkpoint file associated to the run id
+func loadCheckpoint(runID string) (*checkpoint, error) {
+	result := &checkpoint{path: checkpointPath(runID)}
+	content, err := ioutil.ReadFile(result.path)
+	if os.IsNotExist(err) {
+		return nil, errors.WithStackTrace(CheckpointNotFound(runID))
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if err := json.Unmarshal(content, result); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return result, nil
+}
+
+// Initialize the checkpoint used to track the successfully completed modules. If the user asked to resume a
+// previous run, the modules that already succeeded in that run are assumed to be already applied.
+func initCheckpoint(modules map[string]*runningModule) (*checkpoint, error) {
+	terragruntOptions := stackOptions(modules)
+	if terragruntOptions == nil {
+		return nil, nil
+	}
+	command := util.IndexOrDefault(terragruntOptions.TerraformCliArgs, 0, "")
+
+	if terragruntOptions.ResumeRunID == "" {
+		runID := terragruntOptions.Env[options.EnvRunID]
+		if runID == "" || !util.ListContainsElement(checkpointCommands, command) {
+			// There is no run id or the command does not modify anything, so we do not track the progress of the run
+			return nil, nil
+		}
+		return &checkpoint{RunID: runID, Command: command, path: checkpointPath(runID)}, nil
+	}
+
+	previous, err := loadCheckpoint(terragruntOptions.ResumeRunID)
+	if err != nil {
+		return nil, err
+	}
+	if previous.Command != command {
+		return nil, errors.WithStackTrace(ResumeCommandMismatch{previous.RunID, previous.Command, command})
+	}
+
+	for _, path := range previous.Succeeded {
+		if module, found := modules[path]; found {
+			terragruntOptions.Logger.Noticef("Module %s already succeeded in run %s and will be skipped", util.GetPathRelativeToWorkingDirMax(path, 3), previous.RunID)
+			module.Module.AssumeAlreadyApplied = true
+		}
+	}
+	return previous, nil
+}
+
+// Record that the module has been successfully completed and save the checkpoint file
+func (checkpoint *checkpoint) recordSuccess(path string) error {
+	if util.ListContainsElement(checkpoint.Succeeded, path) {
+		return nil
+	}
+	checkpoint.Succeeded = append(checkpoint.Succeeded, path)
+	return checkpoint.save()
+}
+
+// Remove the checkpoint file once all the modules have succeeded, there is nothing left to resume
+func (checkpoint *checkpoint) remove() error {
+	if err := os.Remove(checkpoint.path); err != nil && !os.IsNotExist(err) {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// Save the checkpoint to its file
+func (checkpoint *checkpoint) save() error {
+	content, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(checkpoint.path), 0755); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(ioutil.WriteFile(checkpoint.path, content, 0644))
+}
+
+// CheckpointNotFound is the error returned when trying to resume a run that has no checkpoint file
+type CheckpointNotFound string
+
+func (runID CheckpointNotFound) Error() string {
+	return fmt.Sprintf("Unable to resume run %s, no checkpoint found at %s", string(runID), checkpointPath(string(runID)))
+}
+
+// ResumeCommandMismatch is the error returned when trying to resume a run with a different command
+type ResumeCommandMismatch struct {
+	RunID           string
+	PreviousCommand string
+	Command         string
+}
+
+func (err ResumeCommandMismatch) Error() string {
+	return fmt.Sprintf("Run %s was a %s-all command, it cannot be resumed with %s-all", err.RunID, err.PreviousCommand, err.Command)
+}
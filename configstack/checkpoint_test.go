@@ -0,0 +1,149 @@
+package configstack
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+// Create a mock TerragruntOptions object that resumes the given run
+func optionsResumingRun(terragruntConfigPath string, runID string, executed *bool) *options.TerragruntOptions {
+	opts := optionsWithMockTerragruntCommand(terragruntConfigPath, nil, executed)
+	opts.TerraformCliArgs = []string{"apply"}
+	opts.ResumeRunID = runID
+	return opts
+}
+
+func TestRunModulesResumeFromCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	runID := fmt.Sprintf("test-resume-%d", time.Now().UnixNano())
+	previous := &checkpoint{RunID: runID, Command: "apply", Succeeded: []string{"a"}, path: checkpointPath(runID)}
+	assert.Nil(t, previous.save())
+	defer os.Remove(previous.path)
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsResumingRun("a", runID, &aRan),
+	}
+
+	bRan := false
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{moduleA},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsResumingRun("b", runID, &bRan),
+	}
+
+	err := RunModules([]*TerraformModule{moduleA, moduleB})
+	assert.Nil(t, err, "Unexpected error: %v", err)
+
+	assert.False(t, aRan)
+	assert.True(t, bRan)
+	assert.True(t, moduleA.AssumeAlreadyApplied)
+
+	// The checkpoint is removed once all the modules have succeeded
+	_, err = loadCheckpoint(runID)
+	assert.True(t, errors.IsError(err, CheckpointNotFound(runID)))
+}
+
+func TestRunModulesCheckpointOnlyForMutatingCommands(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		command  string
+		expected bool
+	}{
+		{"apply", true},
+		{"destroy", true},
+		{"plan", false},
+		{"output", false},
+	}
+
+	for _, testCase := range testCases {
+		opts := optionsWithMockTerragruntCommand("a", nil, new(bool))
+		opts.TerraformCliArgs = []string{testCase.command}
+		opts.Env[options.EnvRunID] = fmt.Sprintf("test-checkpoint-%s-%d", testCase.command, time.Now().UnixNano())
+		module := &TerraformModule{Path: "a", Dependencies: []*TerraformModule{}, TerragruntOptions: opts}
+
+		runningModules, err := toRunningModules([]*TerraformModule{module}, NormalOrder)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		checkpoint, err := initCheckpoint(runningModules)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expected, checkpoint != nil, testCase.command)
+	}
+}
+
+func TestRunModulesFailedRunKeepsCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	runID := fmt.Sprintf("test-failed-run-%d", time.Now().UnixNano())
+	defer os.Remove(checkpointPath(runID))
+
+	aRan := false
+	optsA := optionsWithMockTerragruntCommand("a", nil, &aRan)
+	optsA.TerraformCliArgs = []string{"apply"}
+	optsA.Env[options.EnvRunID] = runID
+	moduleA := &TerraformModule{Path: "a", Dependencies: []*TerraformModule{}, TerragruntOptions: optsA}
+
+	bRan := false
+	optsB := optionsWithMockTerragruntCommand("b", fmt.Errorf("failure"), &bRan)
+	optsB.TerraformCliArgs = []string{"apply"}
+	optsB.Env[options.EnvRunID] = runID
+	moduleB := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{moduleA}, TerragruntOptions: optsB}
+
+	assert.Error(t, RunModules([]*TerraformModule{moduleA, moduleB}))
+
+	previous, err := loadCheckpoint(runID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"a"}, previous.Succeeded)
+	}
+}
+
+func TestRunModulesResumeUnknownRun(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsResumingRun("a", "unknown-run-id", &aRan),
+	}
+
+	err := RunModules([]*TerraformModule{moduleA})
+	assert.True(t, errors.IsError(err, CheckpointNotFound("unknown-run-id")))
+	assert.False(t, aRan)
+}
+
+func TestRunModulesResumeWithDifferentCommand(t *testing.T) {
+	t.Parallel()
+
+	runID := fmt.Sprintf("test-resume-mismatch-%d", time.Now().UnixNano())
+	previous := &checkpoint{RunID: runID, Command: "destroy", path: checkpointPath(runID)}
+	assert.Nil(t, previous.save())
+	defer os.Remove(previous.path)
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsResumingRun("a", runID, &aRan),
+	}
+
+	err := RunModules([]*TerraformModule{moduleA})
+	assert.True(t, errors.IsError(err, ResumeCommandMismatch{runID, "destroy", "apply"}))
+	assert.False(t, aRan)
+}
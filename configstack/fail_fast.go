@@ -0,0 +1,93 @@
+package configstack
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// Tracks the first module that failed when --terragrunt-fail-fast is specified. Once a module has failed, no other
+// module is started and the running modules are interrupted if --terragrunt-fail-fast-interrupt is specified.
+type failFast struct {
+	mutex     sync.Mutex
+	interrupt bool
+	failed    *TerraformModule
+	err       error
+	running   map[*runningModule]bool
+}
+
+// Returns the fail fast tracker if it has been requested in the options of the stack (nil otherwise)
+func newFailFast(modules map[string]*runningModule) *failFast {
+	terragruntOptions := stackOptions(modules)
+	if terragruntOptions == nil || !(terragruntOptions.FailFast || terragruntOptions.FailFastInterrupt) {
+		return nil
+	}
+	return &failFast{interrupt: terragruntOptions.FailFastInterrupt, running: map[*runningModule]bool{}}
+}
+
+// Register the module as running or return an error if a module already failed
+func (tracker *failFast) start(module *runningModule) error {
+	if tracker == nil {
+		return nil
+	}
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if tracker.failed != nil {
+		return errors.WithStackTrace(ModuleNotStarted{module.Module, tracker.failed, tracker.err})
+	}
+	if module.Module.TerragruntOptions.Signals == nil {
+		// The signals are required to be able to interrupt the module
+		module.Module.TerragruntOptions.Signals = options.NewCommandSignals()
+	}
+	tracker.running[module] = true
+	return nil
+}
+
+// Record that the module has finished. If it is the first failure, the running modules are interrupted if requested.
+func (tracker *failFast) finish(module *runningModule, err error) {
+	if tracker == nil {
+		return
+	}
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	delete(tracker.running, module)
+	if err == nil || tracker.failed != nil {
+		return
+	}
+
+	tracker.failed, tracker.err = module.Module, err
+	terragruntOptions := module.Module.TerragruntOptions
+	terragruntOptions.Logger.Errorf("Module %s failed, no other module will be started (--terragrunt-fail-fast)", util.GetPathRelativeToWorkingDirMax(module.Module.Path, 3))
+	if !tracker.interrupt {
+		return
+	}
+	for running := range tracker.running {
+		terragruntOptions.Logger.Warningf("Interrupting module %s", running.displayName())
+		running.Module.TerragruntOptions.Signals.Interrupt(shell.ForwardedSignals()...)
+	}
+}
+
+// ModuleNotStarted is the error returned for the modules that have not been started because another module failed
+// and --terragrunt-fail-fast has been specified
+type ModuleNotStarted struct {
+	Module *TerraformModule
+	Failed *TerraformModule
+	Err    error
+}
+
+func (e ModuleNotStarted) Error() string {
+	return fmt.Sprintf("Module %s has not been started because module %s failed (--terragrunt-fail-fast)", util.GetPathRelativeToWorkingDirMax(e.Module.Path, 3), util.GetPathRelativeToWorkingDirMax(e.Failed.Path, 3))
+}
+
+func (e ModuleNotStarted) ExitStatus() (int, error) {
+	if exitCode, err := shell.GetExitCode(e.Err); err == nil {
+		return exitCode, nil
+	}
+	return -1, e
+}
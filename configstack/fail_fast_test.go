@@ -0,0 +1,87 @@
+package configstack
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailFastTracker(t *testing.T) {
+	t.Parallel()
+
+	newModule := func(path string) *runningModule {
+		return &runningModule{Module: &TerraformModule{Path: path, TerragruntOptions: options.NewTerragruntOptionsForTest(path)}}
+	}
+	a, b, c := newModule("a"), newModule("b"), newModule("c")
+	errA := fmt.Errorf("Expected error for module a")
+
+	tracker := &failFast{interrupt: true, running: map[*runningModule]bool{}}
+	assert.Nil(t, tracker.start(a))
+	assert.Nil(t, tracker.start(b))
+
+	tracker.finish(a, errA)
+	assert.True(t, b.Module.TerragruntOptions.Signals.Interrupted())
+
+	err := tracker.start(c)
+	assert.Equal(t, ModuleNotStarted{c.Module, a.Module, errA}, errors.Unwrap(err))
+
+	// A nil tracker (fail fast not requested) never prevents the modules from starting
+	var disabled *failFast
+	assert.Nil(t, disabled.start(c))
+	disabled.finish(c, errA)
+}
+
+func TestRunModulesFailFastInterrupt(t *testing.T) {
+	t.Parallel()
+
+	newOptions := func(path string) *options.TerragruntOptions {
+		opts := options.NewTerragruntOptionsForTest(path)
+		opts.FailFast, opts.FailFastInterrupt = true, true
+		return opts
+	}
+
+	errA := fmt.Errorf("Expected error for module a")
+	optionsA := newOptions("a")
+	optionsA.RunTerragrunt = func(*options.TerragruntOptions) error { return errA }
+	moduleA := &TerraformModule{Path: "a", Dependencies: []*TerraformModule{}, Config: config.TerragruntConfig{}, TerragruntOptions: optionsA}
+
+	optionsC := newOptions("c")
+	optionsC.RunTerragrunt = func(terragruntOptions *options.TerragruntOptions) error {
+		// Simulate a running command that stops gracefully when it is interrupted
+		signals := make(chan os.Signal, len(shell.ForwardedSignals()))
+		terragruntOptions.Signals.Register(signals)
+		defer terragruntOptions.Signals.Unregister(signals)
+		select {
+		case <-signals:
+		case <-time.After(5 * time.Second):
+		}
+		return nil
+	}
+	moduleC := &TerraformModule{Path: "c", Dependencies: []*TerraformModule{}, Config: config.TerragruntConfig{}, TerragruntOptions: optionsC}
+
+	bRan := false
+	optionsB := optionsWithMockTerragruntCommand("b", nil, &bRan)
+	optionsB.FailFast, optionsB.FailFastInterrupt = true, true
+	moduleB := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{moduleC}, Config: config.TerragruntConfig{}, TerragruntOptions: optionsB}
+
+	err := RunModules([]*TerraformModule{moduleA, moduleB, moduleC})
+	assert.False(t, bRan)
+
+	if multiError, isMultiError := errors.Unwrap(err).(MultiError); assert.True(t, isMultiError) {
+		found := false
+		for _, moduleErr := range multiError.Errors {
+			if notStarted, isNotStarted := errors.Unwrap(moduleErr).(ModuleNotStarted); isNotStarted && notStarted.Module == moduleB {
+				found = true
+				assert.Equal(t, moduleA, notStarted.Failed)
+			}
+		}
+		assert.True(t, found, "Module b should be reported as not started: %v", err)
+	}
+}
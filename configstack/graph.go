@@ -1,6 +1,11 @@
 package configstack
 
 import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/gruntwork-io/terragrunt/errors"
 	"github.com/gruntwork-io/terragrunt/util"
 )
@@ -49,3 +54,161 @@ func checkForCyclesUsingDepthFirstSearch(module *TerraformModule, visitedPaths *
 
 	return nil
 }
+
+// A node of the dependency graph of a stack
+type graphNode struct {
+	id, label      string
+	module         *TerraformModule
+	external       bool
+	alreadyApplied bool
+	inCycle        bool
+}
+
+// An edge of the dependency graph of a stack (from a module to one of its dependencies)
+type graphEdge struct {
+	from, to *graphNode
+	inCycle  bool
+}
+
+// Returns the nodes (sorted by path) and edges of the dependency graph of the stack. The nodes that are part of the
+// cycle (if any) and the edges between them are flagged.
+func (stack *Stack) graph(cycle DependencyCycle, absolute bool) ([]*graphNode, []graphEdge) {
+	stackPath, err := util.CanonicalPath(stack.Path, ".")
+	if err != nil {
+		stackPath = stack.Path
+	}
+
+	nodes := map[string]*graphNode{}
+	var addNode func(module *TerraformModule)
+	addNode = func(module *TerraformModule) {
+		if _, found := nodes[module.Path]; found {
+			return
+		}
+		label := module.Path
+		if !absolute {
+			label = util.GetPathRelativeToWorkingDir(module.Path)
+		}
+		nodes[module.Path] = &graphNode{
+			label:          label,
+			module:         module,
+			external:       module.Path != stackPath && !isInFolder(module.Path, stackPath),
+			alreadyApplied: module.AssumeAlreadyApplied,
+			inCycle:        util.ListContainsElement(cycle, module.Path),
+		}
+		for _, dependency := range module.Dependencies {
+			addNode(dependency)
+		}
+	}
+	for _, module := range stack.Modules {
+		addNode(module)
+	}
+
+	sortedNodes := make([]*graphNode, 0, len(nodes))
+	for _, node := range nodes {
+		sortedNodes = append(sortedNodes, node)
+	}
+	sort.Slice(sortedNodes, func(i, j int) bool { return sortedNodes[i].module.Path < sortedNodes[j].module.Path })
+
+	edges := []graphEdge{}
+	for i, node := range sortedNodes {
+		node.id = fmt.Sprintf("m%d", i)
+		for _, dependency := range node.module.Dependencies {
+			edges = append(edges, graphEdge{node, nodes[dependency.Path], isCycleEdge(cycle, node.module.Path, dependency.Path)})
+		}
+	}
+	return sortedNodes, edges
+}
+
+// Returns true if the edge from a module to one of its dependencies is part of the cycle
+func isCycleEdge(cycle DependencyCycle, from, to string) bool {
+	for i := 0; i+1 < len(cycle); i++ {
+		if cycle[i] == from && cycle[i+1] == to {
+			return true
+		}
+	}
+	return false
+}
+
+// DotGraph renders the dependency graph of the stack in Graphviz dot format. External dependencies are dashed, the
+// modules assumed to be already applied are filled in grey and the dependency cycle (if any) is highlighted in red.
+func (stack *Stack) DotGraph(cycle DependencyCycle, absolute bool) string {
+	nodes, edges := stack.graph(cycle, absolute)
+
+	var buffer bytes.Buffer
+	buffer.WriteString("digraph stack {\n")
+	buffer.WriteString("  node [shape=box];\n")
+	for _, node := range nodes {
+		label := node.label
+		attributes := []string{}
+		styles := []string{}
+		if node.external {
+			label += "\n(external)"
+			styles = append(styles, "dashed")
+		}
+		if node.alreadyApplied {
+			label += "\n(already applied)"
+			styles = append(styles, "filled")
+			attributes = append(attributes, `fillcolor="lightgrey"`)
+		}
+		if node.inCycle {
+			attributes = append(attributes, `color="red"`)
+		}
+		if len(styles) > 0 {
+			attributes = append(attributes, fmt.Sprintf("style=%q", strings.Join(styles, ",")))
+		}
+		attributes = append([]string{fmt.Sprintf("label=%q", label)}, attributes...)
+		fmt.Fprintf(&buffer, "  %s [%s];\n", node.id, strings.Join(attributes, ", "))
+	}
+	for _, edge := range edges {
+		if edge.inCycle {
+			fmt.Fprintf(&buffer, "  %s -> %s [color=\"red\"];\n", edge.from.id, edge.to.id)
+		} else {
+			fmt.Fprintf(&buffer, "  %s -> %s;\n", edge.from.id, edge.to.id)
+		}
+	}
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+// MermaidGraph renders the dependency graph of the stack in Mermaid flowchart format. External dependencies are
+// dashed, the modules assumed to be already applied are filled in grey and the dependency cycle (if any) is
+// highlighted in red.
+func (stack *Stack) MermaidGraph(cycle DependencyCycle, absolute bool) string {
+	nodes, edges := stack.graph(cycle, absolute)
+
+	var buffer bytes.Buffer
+	buffer.WriteString("graph TD\n")
+	classes := []string{}
+	for _, node := range nodes {
+		label := strings.Replace(node.label, `"`, "#quot;", -1)
+		if node.external {
+			label += "<br/>(external)"
+			classes = append(classes, fmt.Sprintf("  class %s external", node.id))
+		}
+		if node.alreadyApplied {
+			label += "<br/>(already applied)"
+			classes = append(classes, fmt.Sprintf("  class %s applied", node.id))
+		}
+		if node.inCycle {
+			classes = append(classes, fmt.Sprintf("  class %s cycle", node.id))
+		}
+		fmt.Fprintf(&buffer, "  %s[\"%s\"]\n", node.id, label)
+	}
+	cycleEdges := []string{}
+	for i, edge := range edges {
+		fmt.Fprintf(&buffer, "  %s --> %s\n", edge.from.id, edge.to.id)
+		if edge.inCycle {
+			cycleEdges = append(cycleEdges, fmt.Sprint(i))
+		}
+	}
+	buffer.WriteString("  classDef external stroke-dasharray: 5 5\n")
+	buffer.WriteString("  classDef applied fill:#ddd\n")
+	buffer.WriteString("  classDef cycle stroke:red,stroke-width:2px\n")
+	for _, class := range classes {
+		buffer.WriteString(class + "\n")
+	}
+	if len(cycleEdges) > 0 {
+		fmt.Fprintf(&buffer, "  linkStyle %s stroke:red,stroke-width:2px\n", strings.Join(cycleEdges, ","))
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -84,3 +84,68 @@ func TestCheckForCycles(t *testing.T) {
 		}
 	}
 }
+
+// Returns a stack where a -> b -> a is a cycle and a depends on an external module assumed to be already applied
+func createGraphTestStack() (*Stack, DependencyCycle) {
+	external := &TerraformModule{Path: "/other/vpc", AssumeAlreadyApplied: true}
+	a := &TerraformModule{Path: "/stack/a", Dependencies: []*TerraformModule{}}
+	b := &TerraformModule{Path: "/stack/b", Dependencies: []*TerraformModule{a}}
+	a.Dependencies = append(a.Dependencies, b, external)
+
+	stack := &Stack{Path: "/stack", Modules: []*TerraformModule{a, b, external}}
+	return stack, errors.Unwrap(stack.CheckForCycles()).(DependencyCycle)
+}
+
+func TestDotGraph(t *testing.T) {
+	t.Parallel()
+
+	stack, cycle := createGraphTestStack()
+	expected := `digraph stack {
+  node [shape=box];
+  m0 [label="/other/vpc\n(external)\n(already applied)", fillcolor="lightgrey", style="dashed,filled"];
+  m1 [label="/stack/a", color="red"];
+  m2 [label="/stack/b", color="red"];
+  m1 -> m2 [color="red"];
+  m1 -> m0;
+  m2 -> m1 [color="red"];
+}`
+	assert.Equal(t, expected, stack.DotGraph(cycle, true))
+}
+
+func TestMermaidGraph(t *testing.T) {
+	t.Parallel()
+
+	stack, cycle := createGraphTestStack()
+	expected := `graph TD
+  m0["/other/vpc<br/>(external)<br/>(already applied)"]
+  m1["/stack/a"]
+  m2["/stack/b"]
+  m1 --> m2
+  m1 --> m0
+  m2 --> m1
+  classDef external stroke-dasharray: 5 5
+  classDef applied fill:#ddd
+  classDef cycle stroke:red,stroke-width:2px
+  class m0 external
+  class m0 applied
+  class m1 cycle
+  class m2 cycle
+  linkStyle 0,2 stroke:red,stroke-width:2px`
+	assert.Equal(t, expected, stack.MermaidGraph(cycle, true))
+}
+
+func TestDotGraphWithoutCycle(t *testing.T) {
+	t.Parallel()
+
+	a := &TerraformModule{Path: "/stack/a"}
+	b := &TerraformModule{Path: "/stack/b", Dependencies: []*TerraformModule{a}}
+	stack := &Stack{Path: "/stack", Modules: []*TerraformModule{b, a}}
+
+	expected := `digraph stack {
+  node [shape=box];
+  m0 [label="/stack/a"];
+  m1 [label="/stack/b"];
+  m1 -> m0;
+}`
+	assert.Equal(t, expected, stack.DotGraph(nil, true))
+}
@@ -80,6 +80,19 @@ func ResolveTerraformModules(terragruntConfigPaths []string, terragruntOptions *
 		return []*TerraformModule{}, err
 	}
 
+	if terragruntOptions.InferDependencies {
+		if err := inferRemoteStateDependencies(modules, terragruntOptions); err != nil {
+			return []*TerraformModule{}, err
+		}
+	}
+
+	if terragruntOptions.IncludeDependencies && (len(terragruntOptions.IncludeDirs) > 0 || len(terragruntOptions.ExcludeDirs) > 0) {
+		canonicalTerragruntConfigPaths, err = resolveExcludedDependencies(canonicalTerragruntConfigPaths, modules, terragruntOptions)
+		if err != nil {
+			return []*TerraformModule{}, err
+		}
+	}
+
 	externalDependencies, err := resolveExternalDependenciesForModules(canonicalTerragruntConfigPaths, modules, terragruntOptions)
 	if err != nil {
 		return []*TerraformModule{}, err
@@ -134,6 +147,55 @@ func resolveTerraformModule(terragruntConfigPath string, terragruntOptions *opti
 	return &TerraformModule{Path: modulePath, Config: *terragruntConfig, TerragruntOptions: opts}, nil
 }
 
+// Look through the dependencies of the modules in the given map and add the dependencies that are within the working
+// directory, but that have been excluded by --terragrunt-include-dir or --terragrunt-exclude-dir. The dependencies of
+// these modules are also added until the graph is complete. Return the updated list of Terragrunt config paths.
+func resolveExcludedDependencies(canonicalTerragruntConfigPaths []string, moduleMap map[string]*TerraformModule, terragruntOptions *options.TerragruntOptions) ([]string, error) {
+	workingDir, err := util.CanonicalPath(terragruntOptions.WorkingDir, ".")
+	if err != nil {
+		return canonicalTerragruntConfigPaths, err
+	}
+
+	pending := make([]*TerraformModule, 0, len(moduleMap))
+	for _, module := range moduleMap {
+		pending = append(pending, module)
+	}
+
+	for len(pending) > 0 {
+		module := pending[0]
+		pending = pending[1:]
+		if module.Config.Dependencies == nil {
+			continue
+		}
+
+		for _, dependency := range module.Config.Dependencies.Paths {
+			dependencyPath, err := util.CanonicalPath(dependency, module.Path)
+			if err != nil {
+				return canonicalTerragruntConfigPaths, err
+			}
+
+			terragruntConfigPath := config.DefaultConfigPath(dependencyPath)
+			if util.ListContainsElement(canonicalTerragruntConfigPaths, terragruntConfigPath) || !isInFolder(dependencyPath, workingDir) {
+				// The dependency is already part of the stack or it is an external dependency
+				continue
+			}
+
+			dependencyModule, err := resolveTerraformModule(terragruntConfigPath, terragruntOptions)
+			if err != nil {
+				return canonicalTerragruntConfigPaths, err
+			}
+			canonicalTerragruntConfigPaths = append(canonicalTerragruntConfigPaths, terragruntConfigPath)
+			if dependencyModule != nil {
+				terragruntOptions.Logger.Infof("Module %s is included since it is a dependency of %s", util.GetPathRelativeToWorkingDirMax(dependencyModule.Path, 3), util.GetPathRelativeToWorkingDirMax(module.Path, 3))
+				moduleMap[dependencyModule.Path] = dependencyModule
+				pending = append(pending, dependencyModule)
+			}
+		}
+	}
+
+	return canonicalTerragruntConfigPaths, nil
+}
+
 // Look through the dependencies of the modules in the given map and resolve the "external" dependency paths listed in
 // each modules config (i.e. those dependencies not in the given list of Terragrunt config canonical file paths).
 // These external dependencies are outside of the current working directory, which means they may not be part of the
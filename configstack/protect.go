@@ -0,0 +1,76 @@
+package configstack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// Returns the planned changes that are not allowed by the protect blocks of the module. If the plan could not be
+// rendered as JSON, the resources are unknown and any destruction is considered as a violation.
+func protectionViolations(module TerraformModule, changes planChanges) (violations []string) {
+	if len(module.Config.Protect) == 0 {
+		return nil
+	}
+	if len(changes.Resources) == 0 && changes.Destroy > 0 {
+		return []string{fmt.Sprintf("Unable to verify the protected resources, %d resource(s) to destroy", changes.Destroy)}
+	}
+	for _, resource := range changes.Resources {
+		if pattern := module.Config.Protect.Protects(resource.Address, resource.Action); pattern != "" {
+			violations = append(violations, fmt.Sprintf("%s would be %sd (protected by %s)", resource.Address, resource.Action, pattern))
+		}
+	}
+	return
+}
+
+// Warn the user that apply-all will refuse the modules whose plan changes protected resources
+func warnAboutProtectedResources(terragruntOptions *options.TerragruntOptions, results []moduleResult) {
+	var modules []string
+	for _, result := range results {
+		if len(result.Violations) > 0 {
+			modules = append(modules, util.GetPathRelativeToWorkingDir(result.Module.Path))
+		}
+	}
+	if len(modules) > 0 {
+		terragruntOptions.Logger.Warningf("The plan of %s would destroy or replace protected resources, apply-all will refuse them unless --terragrunt-allow-destroy is specified", strings.Join(modules, ", "))
+	}
+}
+
+// Make the module plan its changes before applying them. The saved plan is only applied (from the working directory
+// where it has been made) if it does not destroy or replace any protected resource.
+func guardProtectedResources(module *TerraformModule) {
+	module.TerragruntOptions.ValidatePlan = func(terragruntOptions *options.TerragruntOptions, planFile string) error {
+		if !terragruntOptions.SupportsJSONPlan() {
+			return errors.WithStackTrace(ProtectedResourcesViolation{module, []string{"Unable to verify the protected resources, terraform 0.12 or later is required to render the plan as JSON"}})
+		}
+
+		planModule := *module
+		planModule.TerragruntOptions = terragruntOptions
+		content, err := showPlanAsJSON(planModule, planFile)
+		if err != nil {
+			return errors.WithStackTrace(ProtectedResourcesViolation{module, []string{fmt.Sprintf("Unable to verify the protected resources: %v", err)}})
+		}
+		_, _, changes, err := extractSummaryResultFromJSONPlan(content)
+		if err != nil {
+			return err
+		}
+		if violations := protectionViolations(*module, changes); len(violations) > 0 {
+			return errors.WithStackTrace(ProtectedResourcesViolation{module, violations})
+		}
+		return nil
+	}
+}
+
+// ProtectedResourcesViolation is the error returned by apply-all for a module whose plan destroys or replaces
+// protected resources
+type ProtectedResourcesViolation struct {
+	Module     *TerraformModule
+	Violations []string
+}
+
+func (err ProtectedResourcesViolation) Error() string {
+	return fmt.Sprintf("Module %s has not been applied because it would destroy or replace protected resources (use --terragrunt-allow-destroy to proceed):\n  %s", util.GetPathRelativeToWorkingDirMax(err.Module.Path, 3), strings.Join(err.Violations, "\n  "))
+}
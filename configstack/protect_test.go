@@ -0,0 +1,56 @@
+package configstack
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectionViolations(t *testing.T) {
+	t.Parallel()
+
+	module := TerraformModule{
+		Path:              "/stack/db",
+		Config:            config.TerragruntConfig{Protect: config.ProtectList{{Resources: []string{"aws_rds_*"}}}},
+		TerragruntOptions: mockOptions,
+	}
+	changes := planChanges{
+		Add: 1, Change: 1, Destroy: 1, Replace: 1,
+		Resources: []resourceChange{
+			{"aws_rds_cluster.main", "replace"},
+			{"aws_rds_cluster_parameter_group.main", "update"},
+			{"aws_security_group.db", "delete"},
+		},
+	}
+
+	assert.Equal(t, []string{"aws_rds_cluster.main would be replaced (protected by aws_rds_*)"}, protectionViolations(module, changes))
+
+	// Without JSON plan, the resources are unknown
+	assert.Len(t, protectionViolations(module, planChanges{Destroy: 2}), 1)
+	assert.Empty(t, protectionViolations(module, planChanges{Add: 2}))
+
+	// There is no violation if the module has no protect block
+	assert.Empty(t, protectionViolations(TerraformModule{Path: "/stack/app", TerragruntOptions: mockOptions}, changes))
+}
+
+func TestGuardProtectedResourcesWithOldTerraform(t *testing.T) {
+	t.Parallel()
+
+	module := &TerraformModule{
+		Path:              "/stack/db",
+		Config:            config.TerragruntConfig{Protect: config.ProtectList{{Resources: []string{"aws_rds_*"}}}},
+		TerragruntOptions: mockOptions.Clone(mockOptions.TerragruntConfigPath),
+	}
+	guardProtectedResources(module)
+
+	// The plan cannot be rendered as JSON, so the protected resources cannot be verified
+	module.TerragruntOptions.TerraformVersion = version.Must(version.NewVersion("0.11.14"))
+	err := module.TerragruntOptions.ValidatePlan(module.TerragruntOptions, "/tmp/apply.tfplan")
+	violation, isViolation := errors.Unwrap(err).(ProtectedResourcesViolation)
+	if assert.True(t, isViolation, "Unexpected error %v", err) {
+		assert.Equal(t, module, violation.Module)
+	}
+}
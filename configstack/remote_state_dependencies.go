@@ -0,0 +1,179 @@
+package configstack
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/coveo/gotemplate/hcl"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// A terraform_remote_state data source found in the terraform files of a module
+type remoteStateReference struct {
+	Name     string
+	Location string
+}
+
+// Add the dependencies inferred from the terraform_remote_state data sources of the modules. A module depends on
+// another module of the stack if it reads the state that this module stores in S3 (same bucket and key). The inferred
+// dependencies are added to the dependencies paths of the module configuration.
+func inferRemoteStateDependencies(moduleMap map[string]*TerraformModule, terragruntOptions *options.TerragruntOptions) error {
+	states := map[string]*TerraformModule{}
+	for _, module := range moduleMap {
+		if module.Config.RemoteState != nil && module.Config.RemoteState.Backend == "s3" {
+			if location := s3StateLocation(module.Config.RemoteState.Config); location != "" {
+				states[location] = module
+			}
+		}
+	}
+	if len(states) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(moduleMap))
+	for path := range moduleMap {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		module := moduleMap[path]
+		references, err := findRemoteStateReferences(module, terragruntOptions)
+		if err != nil {
+			return err
+		}
+		for _, reference := range references {
+			dependency := states[reference.Location]
+			if dependency == nil || dependency == module {
+				continue
+			}
+			declared, err := module.declaresDependency(dependency.Path)
+			if err != nil {
+				return err
+			}
+			if declared {
+				continue
+			}
+
+			terragruntOptions.Logger.Infof("Module %s depends on %s (inferred from data.terraform_remote_state.%s)", util.GetPathRelativeToWorkingDirMax(module.Path, 3), util.GetPathRelativeToWorkingDirMax(dependency.Path, 3), reference.Name)
+			if module.Config.Dependencies == nil {
+				module.Config.Dependencies = &config.ModuleDependencies{}
+			}
+			module.Config.Dependencies.Paths = append(module.Config.Dependencies.Paths, dependency.Path)
+		}
+	}
+	return nil
+}
+
+// Indicates if the dependency is already declared in the dependencies paths of the module
+func (module *TerraformModule) declaresDependency(path string) (bool, error) {
+	if module.Config.Dependencies == nil {
+		return false, nil
+	}
+	for _, dependencyPath := range module.Config.Dependencies.Paths {
+		dependencyPath, err := util.CanonicalPath(dependencyPath, module.Path)
+		if err != nil {
+			return false, err
+		}
+		if dependencyPath == path {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Returns the bucket/key identifying a state stored in S3 or an empty string if they are not known (i.e. they are
+// interpolated from terraform variables)
+func s3StateLocation(stateConfig map[string]interface{}) string {
+	bucket, _ := stateConfig["bucket"].(string)
+	key, _ := stateConfig["key"].(string)
+	if bucket == "" || key == "" || strings.Contains(bucket+key, "${") {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", bucket, strings.TrimPrefix(key, "/"))
+}
+
+// Returns the terraform_remote_state data sources using the s3 backend in the terraform files of the module (the
+// module folder and its source if it is a local folder). The files that cannot be parsed are ignored.
+func findRemoteStateReferences(module *TerraformModule, terragruntOptions *options.TerragruntOptions) (references []remoteStateReference, err error) {
+	folders := []string{module.Path}
+	if localTerraformSource(module) != "" {
+		// The double slash indicates a sub folder within the source
+		source, err := util.CanonicalPath(strings.Replace(module.Config.Terraform.Source, "//", "/", 1), module.Path)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, source)
+	}
+
+	for _, folder := range folders {
+		files, err := filepath.Glob(filepath.Join(folder, "*.tf"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			fileReferences, err := parseRemoteStateReferences(content)
+			if err != nil {
+				terragruntOptions.Logger.Debugf("Unable to look for terraform_remote_state in %s: %v", file, err)
+				continue
+			}
+			references = append(references, fileReferences...)
+		}
+	}
+	return
+}
+
+// Returns the terraform_remote_state data sources using the s3 backend defined in the terraform file content
+func parseRemoteStateReferences(content []byte) (references []remoteStateReference, err error) {
+	if !strings.Contains(string(content), "terraform_remote_state") {
+		return nil, nil
+	}
+
+	var data map[string]interface{}
+	if err = hcl.Unmarshal(content, &data); err != nil {
+		return
+	}
+
+	for _, dataSources := range hclObjects(data["data"]) {
+		for _, remoteStates := range hclObjects(dataSources["terraform_remote_state"]) {
+			for name, definitions := range remoteStates {
+				for _, definition := range hclObjects(definitions) {
+					if definition["backend"] != "s3" {
+						continue
+					}
+					for _, stateConfig := range hclObjects(definition["config"]) {
+						if location := s3StateLocation(stateConfig); location != "" {
+							references = append(references, remoteStateReference{name, location})
+						}
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(references, func(i, j int) bool { return references[i].Name < references[j].Name })
+	return
+}
+
+// Returns the objects defined by an HCL value (a block could be decoded as a single object or as a list of objects)
+func hclObjects(value interface{}) (result []map[string]interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result = append(result, value)
+	case []interface{}:
+		for _, item := range value {
+			result = append(result, hclObjects(item)...)
+		}
+	case []map[string]interface{}:
+		result = append(result, value...)
+	}
+	return
+}
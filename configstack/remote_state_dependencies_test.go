@@ -0,0 +1,92 @@
+package configstack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRemoteStateReferences(t *testing.T) {
+	t.Parallel()
+
+	content := `
+data "terraform_remote_state" "vpc" {
+  backend = "s3"
+  config {
+    bucket = "states"
+    key    = "/vpc/terraform.tfstate"
+  }
+}
+
+data "terraform_remote_state" "db" {
+  backend = "s3"
+  config = {
+    bucket = "states"
+    key    = "db/terraform.tfstate"
+  }
+}
+
+data "terraform_remote_state" "interpolated" {
+  backend = "s3"
+  config {
+    bucket = "states"
+    key    = "${var.env}/terraform.tfstate"
+  }
+}
+
+data "terraform_remote_state" "local" {
+  backend = "local"
+  config {
+    path = "../vpc/terraform.tfstate"
+  }
+}
+`
+
+	references, err := parseRemoteStateReferences([]byte(content))
+	assert.Nil(t, err)
+	assert.Equal(t, []remoteStateReference{{"db", "states/db/terraform.tfstate"}, {"vpc", "states/vpc/terraform.tfstate"}}, references)
+}
+
+func TestInferRemoteStateDependencies(t *testing.T) {
+	t.Parallel()
+
+	stackPath := createTempFolder(t)
+	defer os.RemoveAll(stackPath)
+
+	newModule := func(name, content string, dependencies ...string) *TerraformModule {
+		modulePath := filepath.Join(stackPath, name)
+		createDirIfNotExist(t, modulePath)
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(modulePath, "main.tf"), []byte(content), 0644))
+		module := &TerraformModule{Path: modulePath, TerragruntOptions: mockOptions}
+		module.Config.RemoteState = &remote.RemoteState{Backend: "s3", Config: map[string]interface{}{"bucket": "states", "key": name + "/terraform.tfstate"}}
+		if len(dependencies) > 0 {
+			module.Config.Dependencies = &config.ModuleDependencies{Paths: dependencies}
+		}
+		return module
+	}
+	remoteState := func(name string) string {
+		return `data "terraform_remote_state" "` + name + `" {
+  backend = "s3"
+  config {
+    bucket = "states"
+    key    = "` + name + `/terraform.tfstate"
+  }
+}
+`
+	}
+
+	vpc := newModule("vpc", "")
+	db := newModule("db", remoteState("vpc")+remoteState("external"))
+	app := newModule("app", remoteState("vpc")+remoteState("db"), "../vpc")
+	moduleMap := map[string]*TerraformModule{vpc.Path: vpc, db.Path: db, app.Path: app}
+
+	assert.Nil(t, inferRemoteStateDependencies(moduleMap, mockOptions))
+	assert.Nil(t, vpc.Config.Dependencies)
+	assert.Equal(t, []string{vpc.Path}, db.Config.Dependencies.Paths)
+	assert.Equal(t, []string{"../vpc", db.Path}, app.Config.Dependencies.Paths)
+}
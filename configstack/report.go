@@ -0,0 +1,248 @@
+package configstack
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The possible status of a module in the run report
+const (
+	ReportSucceeded = "succeeded"
+	ReportFailed    = "failed"
+	ReportSkipped   = "skipped"     // The module has been assumed as already applied
+	ReportNotRun    = "not_started" // The module has not been started (i.e. because of an error in a dependency)
+	ReportTimedOut  = "timed_out"   // The module has been interrupted because it exceeded its timeout or the deadline
+)
+
+// RunReport represents the machine-readable result of a *-all command
+type RunReport struct {
+	RunID     string         `json:"run_id,omitempty"`
+	Command   string         `json:"command"`
+	StartTime time.Time      `json:"start_time"`
+	EndTime   time.Time      `json:"end_time"`
+	ExitCode  int            `json:"exit_code"`
+	Modules   []ModuleReport `json:"modules"`
+}
+
+// ModuleReport represents the result of a single module within a RunReport
+type ModuleReport struct {
+	Path         string      `json:"path"`
+	Dependencies []string    `json:"dependencies,omitempty"`
+	Status       string      `json:"status"`
+	WorkerID     int         `json:"worker_id,omitempty"`
+	StartTime    *time.Time  `json:"start_time,omitempty"`
+	EndTime      *time.Time  `json:"end_time,omitempty"`
+	Duration     float64     `json:"duration"`
+	ExitCode     int         `json:"exit_code"`
+	Plan         *PlanReport `json:"plan,omitempty"`
+	Errors       []string    `json:"errors,omitempty"`
+}
+
+// PlanReport represents the summary of a plan for a single module
+type PlanReport struct {
+	Message   string `json:"message"`
+	NbChanges int    `json:"changes"`
+	planChanges
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Write the report of the execution if a report file has been specified in the options
+func writeReport(modules map[string]*runningModule, results *[]moduleResult) {
+	terragruntOptions := stackOptions(modules)
+	if terragruntOptions == nil || terragruntOptions.ReportFile == "" {
+		return
+	}
+
+	var planResults []moduleResult
+	if results != nil {
+		planResults = *results
+	}
+
+	report := newRunReport(modules, planResults)
+	if err := report.save(terragruntOptions.ReportFile); err != nil {
+		terragruntOptions.Logger.Errorf("Unable to write the report to %s: %v", terragruntOptions.ReportFile, err)
+		return
+	}
+	terragruntOptions.Logger.Infof("Report written to %s", terragruntOptions.ReportFile)
+}
+
+// Create a report from the running modules state (and plan results if there are)
+func newRunReport(modules map[string]*runningModule, results []moduleResult) (report RunReport) {
+	planResults := make(map[string]moduleResult, len(results))
+	for _, result := range results {
+		planResults[result.Module.Path] = result
+	}
+
+	for _, module := range modules {
+		moduleReport := newModuleReport(module)
+		if result, ok := planResults[module.Module.Path]; ok {
+			moduleReport.Plan = &PlanReport{result.Message, result.NbChanges, result.Changes, result.Violations}
+		}
+		report.Modules = append(report.Modules, moduleReport)
+
+		if report.Command == "" {
+			report.RunID = module.Module.TerragruntOptions.Env[options.EnvRunID]
+			report.Command = util.IndexOrDefault(module.Module.TerragruntOptions.TerraformCliArgs, 0, "")
+		}
+		if moduleReport.StartTime != nil && (report.StartTime.IsZero() || moduleReport.StartTime.Before(report.StartTime)) {
+			report.StartTime = *moduleReport.StartTime
+		}
+		if moduleReport.EndTime != nil && moduleReport.EndTime.After(report.EndTime) {
+			report.EndTime = *moduleReport.EndTime
+		}
+		if report.ExitCode != UNDEFINED_EXIT_CODE && (moduleReport.ExitCode > report.ExitCode || moduleReport.ExitCode == UNDEFINED_EXIT_CODE) {
+			// As for MultiError, an undefined exit code has precedence over all others
+			report.ExitCode = moduleReport.ExitCode
+		}
+	}
+
+	sort.Slice(report.Modules, func(i, j int) bool { return report.Modules[i].Path < report.Modules[j].Path })
+	return
+}
+
+// Create the report of a single module
+func newModuleReport(module *runningModule) ModuleReport {
+	result := ModuleReport{
+		Path:     util.GetPathRelativeToWorkingDir(module.Module.Path),
+		WorkerID: module.workerID,
+		ExitCode: NORMAL_EXIT_CODE,
+		Status:   ReportSucceeded,
+	}
+
+	for _, dependency := range module.Module.Dependencies {
+		result.Dependencies = append(result.Dependencies, util.GetPathRelativeToWorkingDir(dependency.Path))
+	}
+
+	if !module.startTime.IsZero() {
+		startTime, endTime := module.startTime, module.endTime
+		result.StartTime, result.EndTime = &startTime, &endTime
+		result.Duration = endTime.Sub(startTime).Seconds()
+	}
+
+	switch {
+	case module.Err != nil && module.startTime.IsZero():
+		result.Status = ReportNotRun
+	case isTimedOut(module.Err):
+		result.Status = ReportTimedOut
+	case module.Err != nil:
+		result.Status = ReportFailed
+	case module.Module.AssumeAlreadyApplied:
+		result.Status = ReportSkipped
+	}
+
+	if module.Err != nil {
+		result.Errors = errorChain(module.Err)
+		if exitCode, err := shell.GetExitCode(module.Err); err == nil {
+			result.ExitCode = exitCode
+		} else {
+			result.ExitCode = UNDEFINED_EXIT_CODE
+		}
+	}
+	return result
+}
+
+// Returns the list of error messages from the outermost error to the root cause
+func errorChain(err error) (result []string) {
+	for err != nil {
+		result = append(result, err.Error())
+		switch cause := errors.Unwrap(err).(type) {
+		case dependencyFinishedWithError:
+			err = cause.Err
+		default:
+			err = nil
+		}
+	}
+	return
+}
+
+// Save the report into the file, the format is determined by the file extension
+func (report RunReport) save(path string) error {
+	var content []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		content, err = xml.MarshalIndent(report.junit(), "", "  ")
+		content = append([]byte(xml.Header), content...)
+	default:
+		content, err = json.MarshalIndent(report, "", "  ")
+	}
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(ioutil.WriteFile(path, append(content, '\n'), 0644))
+}
+
+type junitTestSuite struct {
+	XMLName  xml.Name        `xml:"testsuite"`
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+// Convert the report into a JUnit compatible structure (each module is considered as a test case)
+func (report RunReport) junit() junitTestSuite {
+	suite := junitTestSuite{
+		Name:  fmt.Sprintf("terragrunt %s-all", report.Command),
+		Tests: len(report.Modules),
+		Time:  fmt.Sprintf("%.3f", report.EndTime.Sub(report.StartTime).Seconds()),
+	}
+
+	for _, module := range report.Modules {
+		testCase := junitTestCase{
+			Name:      module.Path,
+			ClassName: report.Command,
+			Time:      fmt.Sprintf("%.3f", module.Duration),
+		}
+		if module.Plan != nil {
+			testCase.SystemOut = module.Plan.Message
+		}
+
+		switch module.Status {
+		case ReportFailed, ReportTimedOut:
+			suite.Failures++
+			testCase.Failure = &junitMessage{fmt.Sprintf("Exit code %d", module.ExitCode), strings.Join(module.Errors, "\n")}
+		case ReportSkipped:
+			suite.Skipped++
+			testCase.Skipped = &junitMessage{Message: "Assumed already applied"}
+		case ReportNotRun:
+			suite.Skipped++
+			testCase.Skipped = &junitMessage{"Not started", strings.Join(module.Errors, "\n")}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	return suite
+}
+
+// Returns true if the error indicates that the module has been interrupted because of its timeout or the deadline
+func isTimedOut(err error) bool {
+	_, timedOut := errors.Unwrap(err).(ModuleTimedOut)
+	return timedOut
+}
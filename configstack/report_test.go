@@ -0,0 +1,77 @@
+package configstack
+
+import (
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRunReport(t *testing.T) {
+	t.Parallel()
+
+	moduleA := &TerraformModule{Path: "a", Config: config.TerragruntConfig{}, TerragruntOptions: mockOptions}
+	moduleB := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{moduleA}, Config: config.TerragruntConfig{}, TerragruntOptions: mockOptions}
+	moduleC := &TerraformModule{Path: "c", Config: config.TerragruntConfig{}, TerragruntOptions: mockOptions, AssumeAlreadyApplied: true}
+
+	start := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
+	errA := fmt.Errorf("Expected error for module a")
+
+	runningModuleA := &runningModule{Module: moduleA, Status: Finished, Err: errA, workerID: 1, startTime: start, endTime: start.Add(10 * time.Second)}
+	runningModuleB := &runningModule{Module: moduleB, Status: Finished, Err: dependencyFinishedWithError{moduleB, moduleA, errA}}
+	runningModuleC := &runningModule{Module: moduleC, Status: Finished, workerID: 2, startTime: start.Add(time.Second), endTime: start.Add(2 * time.Second)}
+
+	modules := map[string]*runningModule{"a": runningModuleA, "b": runningModuleB, "c": runningModuleC}
+	results := []moduleResult{{Module: *moduleC, Message: "No change"}}
+
+	report := newRunReport(modules, results)
+
+	assert.Equal(t, start, report.StartTime)
+	assert.Equal(t, start.Add(10*time.Second), report.EndTime)
+	assert.Equal(t, UNDEFINED_EXIT_CODE, report.ExitCode)
+	if assert.Len(t, report.Modules, 3) {
+		a, b, c := report.Modules[0], report.Modules[1], report.Modules[2]
+
+		assert.Equal(t, ReportFailed, a.Status)
+		assert.Equal(t, 1, a.WorkerID)
+		assert.Equal(t, 10.0, a.Duration)
+		assert.Equal(t, []string{errA.Error()}, a.Errors)
+
+		assert.Equal(t, ReportNotRun, b.Status)
+		assert.Equal(t, []string{"a"}, b.Dependencies)
+		assert.Nil(t, b.StartTime)
+		assert.Equal(t, []string{runningModuleB.Err.Error(), errA.Error()}, b.Errors)
+
+		assert.Equal(t, ReportSkipped, c.Status)
+		assert.Equal(t, NORMAL_EXIT_CODE, c.ExitCode)
+		if assert.NotNil(t, c.Plan) {
+			assert.Equal(t, "No change", c.Plan.Message)
+		}
+	}
+}
+
+func TestRunReportJUnit(t *testing.T) {
+	t.Parallel()
+
+	report := RunReport{
+		Command: "apply",
+		Modules: []ModuleReport{
+			{Path: "a", Status: ReportFailed, ExitCode: 1, Errors: []string{"boom"}},
+			{Path: "b", Status: ReportNotRun},
+			{Path: "c", Status: ReportSucceeded, Duration: 1.5},
+		},
+	}
+
+	suite := report.junit()
+	assert.Equal(t, 3, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Skipped)
+	assert.Equal(t, "1.500", suite.Cases[2].Time)
+
+	content, err := xml.Marshal(suite)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), `<failure message="Exit code 1">boom</failure>`)
+}
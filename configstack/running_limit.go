@@ -2,6 +2,9 @@ package configstack
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
@@ -15,20 +18,242 @@ func initWorkers(n int) {
 	if n <= 0 {
 		panic(fmt.Errorf("The number of workers must be greater than 0 (%d)", n))
 	}
-	if burstyLimiter == nil {
-		burstyLimiter = make(chan int, n)
-		for i := 1; i <= n; i++ {
-			time.Sleep(waitTimeBetweenThread * time.Millisecond) // Start workers progressively to avoid throttling
-			burstyLimiter <- i
+	workersMutex.Lock()
+	defer workersMutex.Unlock()
+	if workers == nil {
+		workers = newWorkerPool(n)
+		workers.start(waitTimeBetweenThread * time.Millisecond) // Start workers progressively to avoid throttling
+	}
+}
+
+func nbWorkers() int {
+	workersMutex.Lock()
+	defer workersMutex.Unlock()
+	if workers == nil {
+		return 0
+	}
+	return workers.size
+}
+
+var workers *workerPool
+var workersMutex sync.Mutex
+
+// Distributes the worker slots to the modules that are ready to run. The waiting module with the highest priority is
+// always served first and a module may require more than one slot (see weight). A module that cannot get all its slots
+// blocks the modules with a lower priority to ensure that heavy modules are not delayed indefinitely. However, a module
+// whose concurrency group has reached its limit does not block the modules of other groups.
+type workerPool struct {
+	mutex        sync.Mutex
+	size         int              // The total number of slots
+	added        int              // The number of slots that have been made available so far
+	free         []int            // The ids of the available slots
+	waiting      []*workerRequest // The modules waiting for slots, sorted by priority
+	sequence     int              // Used to preserve the arrival order of modules having the same priority
+	groupLimits  map[string]int   // The maximum number of running modules for each concurrency group
+	groupRunning map[string]int   // The number of running modules for each concurrency group
+}
+
+// A request for worker slots made by a module ready to run
+type workerRequest struct {
+	module   *runningModule
+	weight   int
+	priority int
+	critical int
+	sequence int
+	group    string
+	slots    []int
+	granted  chan []int
+}
+
+func newWorkerPool(size int) *workerPool {
+	return &workerPool{size: size, groupRunning: map[string]int{}}
+}
+
+// Define the maximum number of running modules for each concurrency group
+func (pool *workerPool) setGroupLimits(limits map[string]int) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	pool.groupLimits = limits
+	pool.dispatch()
+}
+
+// Make the slots available. If stagger is specified, the slots are added progressively (one every stagger).
+func (pool *workerPool) start(stagger time.Duration) {
+	if stagger <= 0 {
+		pool.addSlots(pool.size)
+		return
+	}
+	pool.addSlots(1)
+	go func() {
+		for i := 1; i < pool.size; i++ {
+			time.Sleep(stagger)
+			pool.addSlots(1)
 		}
+	}()
+}
+
+// Make n more slots available (up to the size of the pool)
+func (pool *workerPool) addSlots(n int) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	for ; n > 0 && pool.added < pool.size; n-- {
+		pool.added++
+		pool.free = append(pool.free, pool.added)
+	}
+	pool.dispatch()
+}
+
+// Wait until the slots required by the module are available and return the granted request
+func (pool *workerPool) acquire(module *runningModule) *workerRequest {
+	request := pool.enqueue(module)
+	<-request.granted
+	return request
+}
+
+// Give back the slots of a granted request to the pool
+func (pool *workerPool) release(request *workerRequest) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+	pool.free = append(pool.free, request.slots...)
+	if request.group != "" {
+		pool.groupRunning[request.group]--
 	}
+	pool.dispatch()
 }
 
-func nbWorkers() int       { return cap(burstyLimiter) }
-func waitWorker() int      { return <-burstyLimiter }
-func freeWorker(token int) { burstyLimiter <- token }
+// Add the module to the waiting list, the returned request is granted when the slots are available
+func (pool *workerPool) enqueue(module *runningModule) *workerRequest {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
 
-var burstyLimiter chan int
+	weight := module.weight()
+	if weight < 1 {
+		weight = 1
+	} else if weight > pool.size {
+		// A module cannot require more slots than the total number of workers
+		weight = pool.size
+	}
+
+	pool.sequence++
+	request := &workerRequest{
+		module:   module,
+		weight:   weight,
+		priority: module.priority(),
+		critical: module.criticalPath,
+		sequence: pool.sequence,
+		group:    module.concurrencyGroup(),
+		granted:  make(chan []int, 1),
+	}
+	pool.waiting = append(pool.waiting, request)
+	sort.SliceStable(pool.waiting, func(i, j int) bool { return pool.waiting[i].before(pool.waiting[j]) })
+	pool.dispatch()
+	return request
+}
+
+// Grant the free slots to the waiting modules in priority order (the mutex must be held)
+func (pool *workerPool) dispatch() {
+	for i := 0; i < len(pool.waiting); {
+		request := pool.waiting[i]
+		if limit := pool.groupLimits[request.group]; request.group != "" && limit > 0 && pool.groupRunning[request.group] >= limit {
+			// The group of the module is full, but the modules of other groups could run
+			i++
+			continue
+		}
+		if len(pool.free) < request.weight {
+			return
+		}
+
+		pool.waiting = append(pool.waiting[:i], pool.waiting[i+1:]...)
+		request.slots = make([]int, request.weight)
+		copy(request.slots, pool.free)
+		pool.free = pool.free[request.weight:]
+		if request.group != "" {
+			pool.groupRunning[request.group]++
+		}
+		request.granted <- request.slots
+	}
+}
+
+// Returns true if the request must be served before the other one. The priority attribute has precedence, then the
+// module on the longest chain of dependents (critical path) is served first.
+func (request *workerRequest) before(other *workerRequest) bool {
+	if request.priority != other.priority {
+		return request.priority > other.priority
+	}
+	if request.critical != other.critical {
+		return request.critical > other.critical
+	}
+	return request.sequence < other.sequence
+}
+
+// Returns the priority of the module defined in its configuration (0 by default)
+func (module *runningModule) priority() int {
+	if module.Module.Config.Priority == nil {
+		return 0
+	}
+	return *module.Module.Config.Priority
+}
+
+// Returns the number of worker slots consumed by the module defined in its configuration (1 by default)
+func (module *runningModule) weight() int {
+	if module.Module.Config.Weight == nil {
+		return 1
+	}
+	return *module.Module.Config.Weight
+}
+
+// Returns the concurrency group of the module defined in its configuration (empty if there is none)
+func (module *runningModule) concurrencyGroup() string {
+	if module.Module.Config.Group == nil {
+		return ""
+	}
+	// The variables of the group have already been substituted when the configuration was parsed
+	return strings.TrimSpace(*module.Module.Config.Group)
+}
+
+// Returns the limits of the concurrency groups defined in the configuration of the modules. If the modules define
+// different limits for a group, the lowest one is used. The limits specified on the command line have precedence.
+func concurrencyGroupLimits(modules map[string]*runningModule) map[string]int {
+	limits := map[string]int{}
+	for _, module := range modules {
+		for group, limit := range module.Module.Config.GroupLimits {
+			if current, exist := limits[group]; !exist || limit < current {
+				limits[group] = limit
+			}
+		}
+	}
+	if terragruntOptions := stackOptions(modules); terragruntOptions != nil {
+		for group, limit := range terragruntOptions.GroupLimits {
+			limits[group] = limit
+		}
+	}
+	return limits
+}
+
+// Compute the length of the longest chain of modules waiting (directly or indirectly) on each module. This is the
+// critical path used to schedule first the modules that unblock the largest number of sequential modules.
+func computeCriticalPaths(modules map[string]*runningModule) {
+	var compute func(module *runningModule, visiting map[*runningModule]bool) int
+	compute = func(module *runningModule, visiting map[*runningModule]bool) int {
+		if module.criticalPath > 0 || visiting[module] {
+			// Already computed (or cycle, which should have been detected before)
+			return module.criticalPath
+		}
+		visiting[module] = true
+		longest := 0
+		for _, dependent := range module.NotifyWhenDone {
+			if length := compute(dependent, visiting); length > longest {
+				longest = length
+			}
+		}
+		module.criticalPath = longest + 1
+		return module.criticalPath
+	}
+
+	for _, module := range modules {
+		compute(module, map[*runningModule]bool{})
+	}
+}
 
 // OutputPeriodicLogs displays current module output for long running request
 func (module *runningModule) OutputPeriodicLogs(completed *bool) {
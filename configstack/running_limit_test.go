@@ -0,0 +1,192 @@
+package configstack
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+)
+
+// Create a running module with the given scheduling attributes
+func newSchedulingModule(path string, priority, weight, criticalPath int) *runningModule {
+	return &runningModule{
+		Module:       &TerraformModule{Path: path, Config: config.TerragruntConfig{Priority: &priority, Weight: &weight}, TerragruntOptions: mockOptions},
+		criticalPath: criticalPath,
+	}
+}
+
+// Returns the slots granted to the request or nil if the request is still waiting
+func grantedSlots(request *workerRequest) []int {
+	select {
+	case slots := <-request.granted:
+		return slots
+	default:
+		return nil
+	}
+}
+
+func TestWorkerPoolPriority(t *testing.T) {
+	t.Parallel()
+
+	pool := newWorkerPool(1)
+	low := pool.enqueue(newSchedulingModule("low", 0, 1, 1))
+	critical := pool.enqueue(newSchedulingModule("critical", 0, 1, 3))
+	high := pool.enqueue(newSchedulingModule("high", 5, 1, 1))
+	low2 := pool.enqueue(newSchedulingModule("low2", 0, 1, 1))
+
+	// No slot is available until the pool is started
+	assert.Nil(t, grantedSlots(high))
+
+	pool.start(0)
+	assert.Equal(t, []int{1}, grantedSlots(high))
+	assert.Nil(t, grantedSlots(critical))
+
+	pool.release(high)
+	assert.Equal(t, []int{1}, grantedSlots(critical))
+	assert.Nil(t, grantedSlots(low))
+
+	pool.release(critical)
+	assert.Equal(t, []int{1}, grantedSlots(low))
+	assert.Nil(t, grantedSlots(low2))
+
+	pool.release(low)
+	assert.Equal(t, []int{1}, grantedSlots(low2))
+}
+
+func TestWorkerPoolWeight(t *testing.T) {
+	t.Parallel()
+
+	pool := newWorkerPool(3)
+	pool.addSlots(3)
+
+	heavy := pool.enqueue(newSchedulingModule("heavy", 0, 2, 1))
+	assert.Equal(t, []int{1, 2}, grantedSlots(heavy))
+	light := pool.enqueue(newSchedulingModule("light", 0, 1, 1))
+	assert.Equal(t, []int{3}, grantedSlots(light))
+
+	// The heavy module waits for two slots and blocks the modules with a lower priority
+	heavy2 := pool.enqueue(newSchedulingModule("heavy2", 0, 2, 2))
+	light2 := pool.enqueue(newSchedulingModule("light2", 0, 1, 1))
+	pool.release(light)
+	assert.Nil(t, grantedSlots(heavy2))
+	assert.Nil(t, grantedSlots(light2))
+
+	pool.release(heavy)
+	assert.Equal(t, []int{3, 1}, grantedSlots(heavy2))
+	assert.Equal(t, []int{2}, grantedSlots(light2))
+
+	// A module cannot require more slots than the size of the pool
+	huge := pool.enqueue(newSchedulingModule("huge", 0, 10, 1))
+	pool.release(heavy2)
+	assert.Nil(t, grantedSlots(huge))
+	pool.release(light2)
+	assert.Len(t, grantedSlots(huge), 3)
+}
+
+func TestWorkerPoolConcurrencyGroups(t *testing.T) {
+	t.Parallel()
+
+	newGroupModule := func(path, group string) *runningModule {
+		module := newSchedulingModule(path, 0, 1, 1)
+		module.Module.Config.Group = &group
+		return module
+	}
+
+	pool := newWorkerPool(3)
+	pool.setGroupLimits(map[string]int{"db": 1})
+	pool.addSlots(3)
+
+	db1 := pool.enqueue(newGroupModule("db1", "db"))
+	assert.Equal(t, []int{1}, grantedSlots(db1))
+
+	// The group is full, but the modules of other groups are not blocked
+	db2 := pool.enqueue(newGroupModule("db2", "db"))
+	app := pool.enqueue(newGroupModule("app", "app"))
+	other := pool.enqueue(newSchedulingModule("other", 0, 1, 1))
+	assert.Nil(t, grantedSlots(db2))
+	assert.Equal(t, []int{2}, grantedSlots(app))
+	assert.Equal(t, []int{3}, grantedSlots(other))
+
+	pool.release(app)
+	assert.Nil(t, grantedSlots(db2))
+	pool.release(db1)
+	assert.Equal(t, []int{1}, grantedSlots(db2))
+}
+
+func TestConcurrencyGroupIsNotSubstitutedTwice(t *testing.T) {
+	t.Parallel()
+
+	// The group is already substituted when the configuration is parsed, the remaining ${...} are literals
+	module := newSchedulingModule("a", 0, 1, 1)
+	module.Module.TerragruntOptions = mockOptions.Clone(mockOptions.TerragruntConfigPath)
+	module.Module.TerragruntOptions.SetVariable("env", "prod", options.VarParameter)
+	group := " db-${env} "
+	module.Module.Config.Group = &group
+	assert.Equal(t, "db-${env}", module.concurrencyGroup())
+}
+
+func TestConcurrencyGroupLimits(t *testing.T) {
+	t.Parallel()
+
+	moduleA := newSchedulingModule("a", 0, 1, 1)
+	moduleA.Module.Config.GroupLimits = map[string]int{"db": 2, "app": 3}
+	moduleB := newSchedulingModule("b", 0, 1, 1)
+	moduleB.Module.Config.GroupLimits = map[string]int{"db": 1}
+	modules := map[string]*runningModule{"a": moduleA, "b": moduleB}
+	assert.Equal(t, map[string]int{"db": 1, "app": 3}, concurrencyGroupLimits(modules))
+
+	// The limits specified on the command line have precedence
+	terragruntOptions := mockOptions.Clone(mockOptions.TerragruntConfigPath)
+	terragruntOptions.GroupLimits = map[string]int{"app": 1, "web": 2}
+	moduleA.Module.TerragruntOptions, moduleB.Module.TerragruntOptions = terragruntOptions, terragruntOptions
+	assert.Equal(t, map[string]int{"db": 1, "app": 1, "web": 2}, concurrencyGroupLimits(modules))
+}
+
+func TestWorkerPoolProgressiveStart(t *testing.T) {
+	t.Parallel()
+
+	pool := newWorkerPool(2)
+	first := pool.enqueue(newSchedulingModule("first", 0, 1, 1))
+	second := pool.enqueue(newSchedulingModule("second", 0, 1, 1))
+
+	pool.addSlots(1)
+	assert.Equal(t, []int{1}, grantedSlots(first))
+	assert.Nil(t, grantedSlots(second))
+
+	pool.addSlots(5)
+	assert.Equal(t, []int{2}, grantedSlots(second))
+	assert.Equal(t, 2, pool.added)
+}
+
+func TestComputeCriticalPaths(t *testing.T) {
+	t.Parallel()
+
+	// d -> c -> a, b -> a, e
+	moduleA := &TerraformModule{Path: "a", TerragruntOptions: mockOptions}
+	moduleB := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{moduleA}, TerragruntOptions: mockOptions}
+	moduleC := &TerraformModule{Path: "c", Dependencies: []*TerraformModule{moduleA}, TerragruntOptions: mockOptions}
+	moduleD := &TerraformModule{Path: "d", Dependencies: []*TerraformModule{moduleC}, TerragruntOptions: mockOptions}
+	moduleE := &TerraformModule{Path: "e", TerragruntOptions: mockOptions}
+	modules := []*TerraformModule{moduleA, moduleB, moduleC, moduleD, moduleE}
+
+	testCases := []struct {
+		order    DependencyOrder
+		expected map[string]int
+	}{
+		{NormalOrder, map[string]int{"a": 3, "b": 1, "c": 2, "d": 1, "e": 1}},
+		{ReverseOrder, map[string]int{"a": 1, "b": 2, "c": 2, "d": 3, "e": 1}},
+	}
+
+	for _, testCase := range testCases {
+		runningModules, err := toRunningModules(modules, testCase.order)
+		assert.Nil(t, err)
+		computeCriticalPaths(runningModules)
+
+		actual := map[string]int{}
+		for path, module := range runningModules {
+			actual[path] = module.criticalPath
+		}
+		assert.Equal(t, testCase.expected, actual, "Order %v", testCase.order)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/gruntwork-io/terragrunt/util"
 )
@@ -20,6 +23,7 @@ type ModuleStatus int
 const NORMAL_EXIT_CODE = 0
 const ERROR_EXIT_CODE = 1
 const UNDEFINED_EXIT_CODE = -1
+const TIMEOUT_EXIT_CODE = 124
 
 const (
 	Waiting ModuleStatus = iota
@@ -45,9 +49,14 @@ type runningModule struct {
 	Writer         io.Writer
 	Handler        ModuleHandler
 	Mutex          *sync.Mutex // A shared mutex pointer to ensure that there is no concurrency problem when job finish and report
-
-	bufferIndex int // Indicates the position of the buffer that has been flushed to the logger
-	workerID    int
+	Checkpoint     *checkpoint // The shared checkpoint used to record the modules that succeeded (nil if not tracked)
+	FailFast       *failFast   // The shared tracker used to stop the run on the first failure (nil if not requested)
+
+	bufferIndex  int // Indicates the position of the buffer that has been flushed to the logger
+	workerID     int
+	criticalPath int       // The length of the longest chain of modules waiting on this module (including itself)
+	startTime    time.Time // Indicates when the module has actually been started (zero if it never ran)
+	endTime      time.Time // Indicates when the module has finished
 }
 
 func (module runningModule) displayName() string {
@@ -104,11 +113,22 @@ func RunModulesReverseOrder(modules []*TerraformModule) error {
 // This version accepts a function as parameter (see: ModuleHander). The handler is called when the command is
 // completed (either succeeded or failed).
 func RunModulesWithHandler(modules []*TerraformModule, handler ModuleHandler, order DependencyOrder) error {
+	return runModules(modules, handler, order, nil)
+}
+
+// Run the modules and write the report if it has been requested. The plan results are optional and are only
+// available for plan commands.
+func runModules(modules []*TerraformModule, handler ModuleHandler, order DependencyOrder, results *[]moduleResult) error {
 	runningModules, err := toRunningModules(modules, order)
 	if err != nil {
 		return err
 	}
 
+	checkpoint, err := initCheckpoint(runningModules)
+	if err != nil {
+		return err
+	}
+
 	for _, module := range runningModules {
 		// Starts mechanism that control the maximum number of active workers
 		if module.Module.TerragruntOptions.NbWorkers <= 0 {
@@ -117,11 +137,17 @@ func RunModulesWithHandler(modules []*TerraformModule, handler ModuleHandler, or
 		initWorkers(module.Module.TerragruntOptions.NbWorkers)
 		break
 	}
+	workers.setGroupLimits(concurrencyGroupLimits(runningModules))
+
+	failFast := newFailFast(runningModules)
+	computeCriticalPaths(runningModules)
 
 	var waitGroup sync.WaitGroup
 	for _, module := range runningModules {
 		waitGroup.Add(1)
 		module.Handler = handler
+		module.Checkpoint = checkpoint
+		module.FailFast = failFast
 		go func(module *runningModule) {
 			var completed bool
 			defer func() {
@@ -141,7 +167,26 @@ func RunModulesWithHandler(modules []*TerraformModule, handler ModuleHandler, or
 
 	waitGroup.Wait()
 
-	return collectErrors(runningModules)
+	writeReport(runningModules, results)
+
+	err = collectErrors(runningModules)
+	if checkpoint == nil {
+		return err
+	}
+	if err != nil {
+		stackOptions(runningModules).Logger.Noticef("To resume this run and only process the modules that did not succeed, use --terragrunt-resume %s", checkpoint.RunID)
+	} else if removeErr := checkpoint.remove(); removeErr != nil {
+		stackOptions(runningModules).Logger.Warningf("Unable to remove the checkpoint of run %s: %v", checkpoint.RunID, removeErr)
+	}
+	return err
+}
+
+// Returns the options of the first module, they are used as the options of the whole stack
+func stackOptions(modules map[string]*runningModule) *options.TerragruntOptions {
+	for _, module := range modules {
+		return module.Module.TerragruntOptions
+	}
+	return nil
 }
 
 // Convert the list of modules to a map from module path to a runningModule struct. This struct contains information
@@ -212,9 +257,12 @@ func (module *runningModule) dependencies() []string {
 func (module *runningModule) runModuleWhenReady() {
 	err := module.waitForDependencies()
 	if err == nil {
-		module.workerID = waitWorker()
-		defer func() { freeWorker(module.workerID) }()
-		err = module.runNow()
+		request := workers.acquire(module)
+		module.workerID = request.slots[0]
+		defer workers.release(request)
+		if err = module.FailFast.start(module); err == nil {
+			err = module.runNow()
+		}
 	}
 	module.moduleFinished(err)
 }
@@ -232,6 +280,11 @@ func (module *runningModule) waitForDependencies() error {
 
 		depPath := util.GetPathRelativeToWorkingDirMax(doneDependency.Module.Path, 3)
 
+		if notStarted, isNotStarted := errors.Unwrap(doneDependency.Err).(ModuleNotStarted); isNotStarted {
+			// The dependency has not been started because of --terragrunt-fail-fast, so this module is not started either
+			return errors.WithStackTrace(ModuleNotStarted{module.Module, notStarted.Failed, notStarted.Err})
+		}
+
 		if doneDependency.Err != nil {
 			if module.Module.TerragruntOptions.IgnoreDependencyErrors {
 				log.Warningf("Dependency %[1]s of module %[2]s just finished with an error. Module %[2]s will have to return an error too. However, because of --terragrunt-ignore-dependency-errors, module %[2]s will run anyway.", depPath, module.displayName())
@@ -256,11 +309,84 @@ func (module *runningModule) runNow() error {
 	module.Status = Running
 
 	if module.Module.AssumeAlreadyApplied {
+		module.startTime = time.Now()
 		module.Module.TerragruntOptions.Logger.Debugf("Assuming module %s has already been applied and skipping it", module.displayName())
 		return nil
 	}
+
+	timeout, isDeadline, err := module.timeLimit(time.Now())
+	if err != nil {
+		return err
+	}
+	if isDeadline && timeout <= 0 {
+		return errors.WithStackTrace(ModuleTimedOut{Module: module.Module, Deadline: true})
+	}
+
+	module.startTime = time.Now()
 	module.Module.TerragruntOptions.Logger.Debugf("Running module %s now", module.displayName())
-	return module.Module.TerragruntOptions.RunTerragrunt(module.Module.TerragruntOptions)
+	if timeout == 0 {
+		return module.Module.TerragruntOptions.RunTerragrunt(module.Module.TerragruntOptions)
+	}
+	return module.runWithTimeout(timeout, isDeadline)
+}
+
+// The delay given to a module to stop after it has been interrupted, after that delay, its commands are killed
+var interruptGracePeriod = 30 * time.Second
+
+// Returns the maximum duration of the module (0 if there is no limit). The timeout attribute of the module
+// configuration has precedence over --terragrunt-module-timeout, but the global deadline applies in all cases.
+// The returned boolean indicates that the limit is determined by the deadline.
+func (module *runningModule) timeLimit(now time.Time) (timeout time.Duration, isDeadline bool, err error) {
+	terragruntOptions := module.Module.TerragruntOptions
+	if timeout, err = module.Module.Config.GetTimeout(); err != nil {
+		return
+	}
+	if timeout == 0 {
+		timeout = terragruntOptions.ModuleTimeout
+	}
+	if !terragruntOptions.Deadline.IsZero() {
+		if remaining := terragruntOptions.Deadline.Sub(now); timeout == 0 || remaining < timeout {
+			return remaining, true, nil
+		}
+	}
+	return
+}
+
+// Run the module and interrupt it if it does not complete within the timeout. The forwarded signals are sent to the
+// running commands of the module, if they do not stop within the grace period, they are killed and the worker is
+// released once the module has returned.
+func (module *runningModule) runWithTimeout(timeout time.Duration, isDeadline bool) error {
+	terragruntOptions := module.Module.TerragruntOptions
+	if terragruntOptions.Signals == nil {
+		terragruntOptions.Signals = options.NewCommandSignals()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- terragruntOptions.RunTerragrunt(terragruntOptions) }()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	timedOut := ModuleTimedOut{Module: module.Module, Timeout: timeout, Deadline: isDeadline, Started: true}
+	terragruntOptions.Logger.Errorf("%v, sending %v to its running commands", timedOut, shell.ForwardedSignals())
+	if terragruntOptions.Signals.Interrupt(shell.ForwardedSignals()...) == 0 {
+		terragruntOptions.Logger.Debugf("Module %s has no running command to interrupt", module.displayName())
+	}
+
+	select {
+	case <-done:
+	case <-time.After(interruptGracePeriod):
+		// The worker is only released once the module is really stopped to respect the concurrency limits
+		terragruntOptions.Logger.Warningf("Module %s did not stop within %v after being interrupted, killing its running commands", module.displayName(), interruptGracePeriod)
+		terragruntOptions.Signals.Interrupt(os.Kill)
+		<-done
+	}
+	return errors.WithStackTrace(timedOut)
 }
 
 var separator = strings.Repeat("-", 132)
@@ -274,6 +400,7 @@ func (module *runningModule) moduleFinished(moduleErr error) {
 	if module.Handler != nil {
 		output, moduleErr = module.Handler(*module.Module, output, moduleErr)
 	}
+	module.FailFast.finish(module, moduleErr)
 
 	if moduleErr != nil {
 		status = fmt.Sprintf("with an error: %v", moduleErr)
@@ -293,6 +420,13 @@ func (module *runningModule) moduleFinished(moduleErr error) {
 
 	module.Status = Finished
 	module.Err = moduleErr
+	module.endTime = time.Now()
+
+	if moduleErr == nil && module.Checkpoint != nil {
+		if err := module.Checkpoint.recordSuccess(module.Module.Path); err != nil {
+			module.Module.TerragruntOptions.Logger.Warningf("Unable to save the checkpoint for module %s: %v", module.displayName(), err)
+		}
+	}
 
 	for _, toNotify := range module.NotifyWhenDone {
 		toNotify.DependencyDone <- module
@@ -318,6 +452,30 @@ func (e dependencyFinishedWithError) ExitStatus() (int, error) {
 	return -1, e
 }
 
+// ModuleTimedOut is the error returned when a module exceeds its timeout or when the global deadline is reached
+type ModuleTimedOut struct {
+	Module   *TerraformModule
+	Timeout  time.Duration
+	Deadline bool // Indicates that the module has been stopped because of the global deadline
+	Started  bool // Indicates that the module has been interrupted (otherwise, it has not been started)
+}
+
+func (e ModuleTimedOut) Error() string {
+	path := util.GetPathRelativeToWorkingDirMax(e.Module.Path, 3)
+	switch {
+	case !e.Started:
+		return fmt.Sprintf("Module %s has not been started because the deadline has been reached", path)
+	case e.Deadline:
+		return fmt.Sprintf("Module %s has been interrupted because the deadline has been reached", path)
+	default:
+		return fmt.Sprintf("Module %s has been interrupted because it exceeded its timeout of %v", path, e.Timeout)
+	}
+}
+
+func (e ModuleTimedOut) ExitStatus() (int, error) {
+	return TIMEOUT_EXIT_CODE, nil
+}
+
 type MultiError struct {
 	Errors []error
 }
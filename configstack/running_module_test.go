@@ -2,10 +2,15 @@ package configstack
 
 import (
 	"fmt"
+	"os"
+	"testing"
+	"time"
+
 	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/errors"
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 var mockOptions = options.NewTerragruntOptionsForTest("running_module_test")
@@ -1072,3 +1077,115 @@ func TestRunModulesReverseOrderMultipleModulesWithDependenciesLargeGraphPartialF
 	assert.True(t, eRan)
 	assert.True(t, fRan)
 }
+
+func TestRunningModuleTimeLimit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	timeout := func(value string) *string { return &value }
+
+	testCases := []struct {
+		configTimeout      *string
+		moduleTimeout      time.Duration
+		deadline           time.Time
+		expectedTimeout    time.Duration
+		expectedIsDeadline bool
+	}{
+		{nil, 0, time.Time{}, 0, false},
+		{nil, time.Minute, time.Time{}, time.Minute, false},
+		{timeout("10m"), time.Minute, time.Time{}, 10 * time.Minute, false},
+		{timeout("10m"), 0, now.Add(time.Hour), 10 * time.Minute, false},
+		{timeout("10m"), 0, now.Add(time.Minute), time.Minute, true},
+		{nil, 0, now.Add(time.Minute), time.Minute, true},
+		{nil, 0, now.Add(-time.Minute), -time.Minute, true},
+	}
+
+	for _, testCase := range testCases {
+		opts := options.NewTerragruntOptionsForTest("a")
+		opts.ModuleTimeout = testCase.moduleTimeout
+		opts.Deadline = testCase.deadline
+		module := &runningModule{Module: &TerraformModule{Path: "a", Config: config.TerragruntConfig{Timeout: testCase.configTimeout}, TerragruntOptions: opts}}
+
+		actualTimeout, actualIsDeadline, err := module.timeLimit(now)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.expectedTimeout, actualTimeout)
+		assert.Equal(t, testCase.expectedIsDeadline, actualIsDeadline)
+	}
+}
+
+func TestRunModulesTimeout(t *testing.T) {
+	t.Parallel()
+
+	var received os.Signal
+	optionsA := options.NewTerragruntOptionsForTest("a")
+	optionsA.RunTerragrunt = func(terragruntOptions *options.TerragruntOptions) error {
+		// Simulate a running command that waits for a signal
+		signals := make(chan os.Signal, len(shell.ForwardedSignals()))
+		terragruntOptions.Signals.Register(signals)
+		defer terragruntOptions.Signals.Unregister(signals)
+		select {
+		case received = <-signals:
+		case <-time.After(5 * time.Second):
+		}
+		return fmt.Errorf("Interrupted")
+	}
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{Timeout: &[]string{"50ms"}[0]},
+		TerragruntOptions: optionsA,
+	}
+
+	bRan := false
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{moduleA},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand("b", nil, &bRan),
+	}
+
+	err := RunModules([]*TerraformModule{moduleA, moduleB})
+	assert.False(t, bRan)
+	if len(shell.ForwardedSignals()) > 0 {
+		assert.Equal(t, shell.ForwardedSignals()[0], received)
+	}
+
+	exitCode, exitErr := shell.GetExitCode(err)
+	assert.Nil(t, exitErr)
+	assert.Equal(t, TIMEOUT_EXIT_CODE, exitCode)
+
+	if multiError, isMultiError := errors.Unwrap(err).(MultiError); assert.True(t, isMultiError) {
+		assert.Len(t, multiError.Errors, 2)
+		for _, moduleErr := range multiError.Errors {
+			switch moduleErr := errors.Unwrap(moduleErr).(type) {
+			case ModuleTimedOut:
+				assert.Equal(t, moduleA, moduleErr.Module)
+				assert.True(t, moduleErr.Started)
+			case dependencyFinishedWithError:
+				assert.Equal(t, moduleB, moduleErr.Module)
+			default:
+				t.Errorf("Unexpected error %v", moduleErr)
+			}
+		}
+	}
+}
+
+func TestRunModulesDeadlineReached(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	optionsA := optionsWithMockTerragruntCommand("a", nil, &aRan)
+	optionsA.Deadline = time.Now().Add(-time.Second)
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsA,
+	}
+
+	err := RunModules([]*TerraformModule{moduleA})
+	assert.False(t, aRan)
+	if multiError, isMultiError := errors.Unwrap(err).(MultiError); assert.True(t, isMultiError) && assert.Len(t, multiError.Errors, 1) {
+		assert.Equal(t, ModuleTimedOut{Module: moduleA, Deadline: true}, errors.Unwrap(multiError.Errors[0]))
+	}
+}
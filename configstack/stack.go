@@ -3,11 +3,16 @@ package configstack
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/errors"
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
 )
 
 // Represents a stack of Terraform modules (i.e. folders with Terraform templates) that you can "spin up" or
@@ -69,15 +74,91 @@ func (stack *Stack) topologicalSort(module *TerraformModule, visitedModules map[
 	return visitedModules, append(sortedModules, module)
 }
 
-// Plan all the modules in the given stack in their specified order.
+// Plan all the modules in the given stack in their specified order. If --terragrunt-plan-dir is specified, the plans
+// are saved in that folder with a manifest allowing apply-all to apply them later.
 func (stack *Stack) Plan(command string, terragruntOptions *options.TerragruntOptions) error {
 	stack.setTerraformCommand([]string{command})
-	return stack.planWithSummary(terragruntOptions)
+	if terragruntOptions.PlanDir == "" {
+		if !terragruntOptions.SupportsJSONPlan() {
+			// The summary is extracted from the plan output
+			_, err := stack.planWithSummary(terragruntOptions)
+			return err
+		}
+
+		// The plans are saved in a temporary folder to be able to summarize them from their JSON representation
+		planDir, err := ioutil.TempDir("", "terragrunt-plans")
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		defer os.RemoveAll(planDir)
+		for _, module := range stack.Modules {
+			setPlanFileArg(module, filepath.Join(planDir, planFileName(stack.Path, module.Path)))
+		}
+		_, err = stack.planWithSummary(terragruntOptions)
+		return err
+	}
+
+	manifest, err := stack.preparePlanDir(terragruntOptions.PlanDir)
+	if err != nil {
+		return err
+	}
+	results, err := stack.planWithSummary(terragruntOptions)
+	if saveErr := manifest.save(terragruntOptions.PlanDir, results); saveErr != nil {
+		if err == nil {
+			return saveErr
+		}
+		terragruntOptions.Logger.Errorf("Unable to save the plan manifest: %v", saveErr)
+	} else {
+		terragruntOptions.Logger.Noticef("%d plan(s) saved in %s", len(manifest.Modules), terragruntOptions.PlanDir)
+	}
+	return err
+}
+
+// Apply all the modules in the given stack in their specified order. Unless --terragrunt-allow-destroy is specified,
+// the modules having protect blocks are planned first and refused if the plan destroys or replaces protected resources.
+func (stack *Stack) Apply(command string, terragruntOptions *options.TerragruntOptions) error {
+	stack.setTerraformCommand([]string{command, "-input=false"})
+	if !terragruntOptions.AllowDestroy {
+		for _, module := range stack.Modules {
+			if len(module.Config.Protect) > 0 {
+				guardProtectedResources(module)
+			}
+		}
+	}
+	return RunModulesWithHandler(stack.Modules, nil, NormalOrder)
+}
+
+// ApplyPlans applies the plans saved by plan-all in --terragrunt-plan-dir in their specified order. The modules that
+// have no saved plan, whose configuration or source changed since the plan was saved or whose plan destroys protected
+// resources are refused (and so are their dependents).
+func (stack *Stack) ApplyPlans(command string, terragruntOptions *options.TerragruntOptions) error {
+	manifest, err := loadPlanManifest(terragruntOptions.PlanDir)
+	if err != nil {
+		return err
+	}
+
+	for _, module := range stack.Modules {
+		planFile, err := manifest.check(terragruntOptions.PlanDir, stack.Path, module, terragruntOptions.AllowDestroy)
+		if err != nil {
+			module.TerragruntOptions.Logger.Errorf("%v", err)
+			refused := errors.WithStackTrace(err)
+			module.TerragruntOptions.RunTerragrunt = func(*options.TerragruntOptions) error { return refused }
+			continue
+		}
+		// The plan file is added by Terragrunt after all the other arguments
+		module.TerragruntOptions.TerraformCliArgs = append([]string{command, "-input=false"}, module.TerragruntOptions.TerraformCliArgs...)
+		module.TerragruntOptions.PlanFile = planFile
+	}
+	return RunModulesWithHandler(stack.Modules, nil, NormalOrder)
 }
 
-// Output prints the outputs of all the modules in the given stack in their specified order.
+// Output prints the outputs of all the modules in the given stack in their specified order. With -json, the outputs
+// are printed as a single JSON document.
 func (stack *Stack) Output(command string, terragruntOptions *options.TerragruntOptions) error {
 	stack.setTerraformCommand([]string{command})
+	if util.ListContainsElement(terragruntOptions.TerraformCliArgs, "-json") {
+		return stack.outputJSON(terragruntOptions)
+	}
 	handler := func(module TerraformModule, output string, err error) (string, error) {
 		if err != nil && strings.Contains(output, "no outputs defined") {
 			return "", nil
@@ -109,6 +190,17 @@ func FindStackInSubfolders(terragruntOptions *options.TerragruntOptions) (*Stack
 	return createStackForTerragruntConfigPaths(terragruntOptions.WorkingDir, terragruntConfigFiles, terragruntOptions)
 }
 
+// FindStackGraphInSubfolders is similar to FindStackInSubfolders, but it does not fail if there is a dependency cycle
+// between the modules. The stack is returned with the cycle found (if any) to allow rendering it.
+func FindStackGraphInSubfolders(terragruntOptions *options.TerragruntOptions) (*Stack, DependencyCycle, error) {
+	terragruntConfigFiles, err := config.FindConfigFilesInPath(terragruntOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return createStackGraphForTerragruntConfigPaths(terragruntOptions.WorkingDir, terragruntConfigFiles, terragruntOptions)
+}
+
 // Set the command in the TerragruntOptions object of each module in this stack to the given command.
 func (stack *Stack) setTerraformCommand(command []string) {
 	for _, module := range stack.Modules {
@@ -122,21 +214,45 @@ func (stack *Stack) setTerraformCommand(command []string) {
 // Find all the Terraform modules in the folders that contain the given Terragrunt config files and assemble those
 // modules into a Stack object that can be applied or destroyed in a single command
 func createStackForTerragruntConfigPaths(path string, terragruntConfigPaths []string, terragruntOptions *options.TerragruntOptions) (*Stack, error) {
+	stack, cycle, err := createStackGraphForTerragruntConfigPaths(path, terragruntConfigPaths, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+	if cycle != nil {
+		return nil, errors.WithStackTrace(cycle)
+	}
+	return stack, nil
+}
+
+// Find all the Terraform modules in the folders that contain the given Terragrunt config files and assemble those
+// modules into a Stack object. If there is a dependency cycle between the modules, it is returned with the stack.
+func createStackGraphForTerragruntConfigPaths(path string, terragruntConfigPaths []string, terragruntOptions *options.TerragruntOptions) (*Stack, DependencyCycle, error) {
 	if len(terragruntConfigPaths) == 0 {
 		terragruntOptions.Logger.Warning("Could not find any subfolders with Terragrunt configuration files")
 	}
 
 	modules, err := ResolveTerraformModules(terragruntConfigPaths, terragruntOptions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	stack := &Stack{Path: path, Modules: modules}
+	var cycle DependencyCycle
 	if err := stack.CheckForCycles(); err != nil {
-		return nil, err
+		var isCycle bool
+		if cycle, isCycle = errors.Unwrap(err).(DependencyCycle); !isCycle {
+			return nil, nil, err
+		}
 	}
 
-	return stack, nil
+	// An invalid stack is returned as is, there is no need to query git for a stack that cannot be run
+	if cycle == nil && terragruntOptions.ChangedSince != "" {
+		if err := stack.filterChangedModules(terragruntOptions); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return stack, cycle, nil
 }
 
 // Custom error types
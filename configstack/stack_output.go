@@ -0,0 +1,70 @@
+package configstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// Print the outputs of all the modules as a single JSON document mapping the path of each module (relative to the
+// stack) to its outputs. The outputs of the modules are not printed individually.
+func (stack *Stack) outputJSON(terragruntOptions *options.TerragruntOptions) error {
+	var mutex sync.Mutex
+	outputs := make(map[string]json.RawMessage, len(stack.Modules))
+	handler := func(module TerraformModule, output string, err error) (string, error) {
+		if err != nil && strings.Contains(output, "no outputs defined") {
+			output, err = "{}", nil
+		}
+		if err != nil {
+			return output, err
+		}
+
+		value, err := parseJSONOutput(output)
+		if err != nil {
+			return output, errors.WithStackTrace(InvalidJSONOutput{&module, err})
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		outputs[stackRelativePath(stack.Path, module.Path)] = value
+		return "", nil
+	}
+
+	err := RunModulesWithHandler(stack.Modules, handler, NormalOrder)
+	content, marshalErr := json.MarshalIndent(outputs, "", "  ")
+	if marshalErr != nil {
+		return errors.WithStackTrace(marshalErr)
+	}
+	terragruntOptions.Println(string(content))
+	return err
+}
+
+// Extract the JSON document from the output of `terraform output -json`. The output could contain other messages
+// (i.e. printed by hooks), so the document is delimited by the first opening brace and the last closing brace.
+func parseJSONOutput(output string) (json.RawMessage, error) {
+	start, end := strings.Index(output, "{"), strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("No JSON document found")
+	}
+
+	var buffer bytes.Buffer
+	if err := json.Compact(&buffer, []byte(output[start:end+1])); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(buffer.Bytes()), nil
+}
+
+// InvalidJSONOutput is the error returned when the output of a module cannot be parsed as JSON
+type InvalidJSONOutput struct {
+	Module *TerraformModule
+	Err    error
+}
+
+func (err InvalidJSONOutput) Error() string {
+	return fmt.Sprintf("Unable to parse the JSON output of module %s: %v", util.GetPathRelativeToWorkingDirMax(err.Module.Path, 3), err.Err)
+}
@@ -0,0 +1,34 @@
+package configstack
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSONOutput(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		output   string
+		expected string
+		valid    bool
+	}{
+		{"{}", "{}", true},
+		{`{"vpc_id": {"sensitive": false, "type": "string", "value": "vpc-1"}}`, `{"vpc_id":{"sensitive":false,"type":"string","value":"vpc-1"}}`, true},
+		{"Running hook\n{\n  \"a\": {\"value\": 1}\n}\nDone", `{"a":{"value":1}}`, true},
+		{"No outputs", "", false},
+		{"{ invalid }", "", false},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := parseJSONOutput(testCase.output)
+		if !testCase.valid {
+			assert.NotNil(t, err, "For %s", testCase.output)
+			continue
+		}
+		assert.Nil(t, err, "For %s", testCase.output)
+		assert.Equal(t, json.RawMessage(testCase.expected), actual, "For %s", testCase.output)
+	}
+}
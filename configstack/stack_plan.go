@@ -1,6 +1,8 @@
 package configstack
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -14,15 +16,47 @@ import (
 
 // The returned information for each module
 type moduleResult struct {
-	Module    TerraformModule
-	Err       error
-	Message   string
-	NbChanges int
+	Module     TerraformModule
+	Err        error
+	Message    string
+	NbChanges  int
+	Changes    planChanges
+	Violations []string // The planned changes that are not allowed by the protect blocks of the module
+}
+
+// The number of resources affected by a plan (a replaced resource is counted as added and destroyed, like terraform
+// does). The resources are only available if the plan has been rendered as JSON.
+type planChanges struct {
+	Add       int              `json:"add"`
+	Change    int              `json:"change"`
+	Destroy   int              `json:"destroy"`
+	Replace   int              `json:"replace,omitempty"`
+	Resources []resourceChange `json:"resources,omitempty"`
+}
+
+// The action planned on a resource
+type resourceChange struct {
+	Address string `json:"address"`
+	Action  string `json:"action"` // create, update, delete or replace
+}
+
+// The symbols used by terraform to represent the actions
+var resourceActionSymbols = map[string]string{"create": "+", "update": "~", "delete": "-", "replace": "-/+"}
+
+// The subset of the plan rendered by `terraform show -json` that is required to summarize it
+type jsonPlan struct {
+	FormatVersion   string `json:"format_version"`
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
 }
 
 var planResultRegex = regexp.MustCompile(`(\d+) to add, (\d+) to change, (\d+) to destroy.`)
 
-func (stack *Stack) planWithSummary(terragruntOptions *options.TerragruntOptions) error {
+func (stack *Stack) planWithSummary(terragruntOptions *options.TerragruntOptions) ([]moduleResult, error) {
 	// We override the multi errors creator to use a specialized error type for plan
 	// because error severity in plan is not standard (i.e. exit code 2 is less significant that exit code 1).
 	CreateMultiErrors = func(errs []error) error {
@@ -33,8 +67,9 @@ func (stack *Stack) planWithSummary(terragruntOptions *options.TerragruntOptions
 
 	hasChanges := false
 	results := make([]moduleResult, 0, len(stack.Modules))
-	err := RunModulesWithHandler(stack.Modules, getResultHandler(detailedExitCode, &results, &hasChanges), NormalOrder)
+	err := runModules(stack.Modules, getResultHandler(detailedExitCode, &results, &hasChanges), NormalOrder, &results)
 	printSummary(terragruntOptions, results)
+	warnAboutProtectedResources(terragruntOptions, results)
 
 	// If there is no error, but -detail-exitcode is specified, we return an error with the number of changes.
 	if err == nil && detailedExitCode {
@@ -52,11 +87,11 @@ func (stack *Stack) planWithSummary(terragruntOptions *options.TerragruntOptions
 			terragruntOptions.Logger.Noticef("There are no terraform changes but hooks have reported changes.")
 		}
 		if hasChanges {
-			return errors.PlanWithChanges{}
+			return results, errors.PlanWithChanges{}
 		}
 	}
 
-	return err
+	return results, err
 }
 
 // Returns the handler that will be executed after each completion of `terraform plan`
@@ -72,10 +107,10 @@ func getResultHandler(detailedExitCode bool, results *[]moduleResult, hasChanges
 		}
 
 		if output != "" {
-			message, count := extractSummaryResultFromPlan(output)
+			message, count, changes := summarizePlan(module, output, err)
 
 			// We add the result to the result list (there is no concurrency problem because it is handled by the running_module)
-			*results = append(*results, moduleResult{module, err, message, count})
+			*results = append(*results, moduleResult{module, err, message, count, changes, protectionViolations(module, changes)})
 		}
 
 		return output, err
@@ -102,6 +137,12 @@ func printSummary(terragruntOptions *options.TerragruntOptions, results []module
 		}
 
 		terragruntOptions.Printf(format, util.GetPathRelativeToWorkingDir(result.Module.Path), result.Message, errMsg)
+		for _, resource := range result.Changes.Resources {
+			terragruntOptions.Printf("        %3s %s\n", resourceActionSymbols[resource.Action], resource.Address)
+		}
+		for _, violation := range result.Violations {
+			terragruntOptions.Printf("        %3s %s\n", "!", violation)
+		}
 	}
 }
 
@@ -119,30 +160,129 @@ func warnAboutMissingDependencies(module TerraformModule, output string) {
 	}
 }
 
+// Returns the summary of the plan of the module. If the plan has been saved, the summary is built from its JSON
+// representation if terraform is able to render it (>= 0.12), otherwise the output message is parsed.
+func summarizePlan(module TerraformModule, output string, planErr error) (string, int, planChanges) {
+	if !module.TerragruntOptions.SupportsJSONPlan() {
+		return extractSummaryResultFromPlan(output)
+	}
+	if planFile := planFileArg(module.TerragruntOptions.TerraformCliArgs); planFile != "" && planErr == nil && util.FileExists(planFile) {
+		content, err := showPlanAsJSON(module, planFile)
+		if err == nil {
+			var message string
+			var count int
+			var changes planChanges
+			if message, count, changes, err = extractSummaryResultFromJSONPlan(content); err == nil {
+				return message, count, changes
+			}
+		}
+		module.TerragruntOptions.Logger.Warningf("Unable to get the JSON plan of %s, parsing the output instead: %v", util.GetPathRelativeToWorkingDirMax(module.Path, 3), err)
+	}
+	return extractSummaryResultFromPlan(output)
+}
+
+// Returns the plan file specified by the -out argument (empty if there is none)
+func planFileArg(args []string) string {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-out=") {
+			return strings.TrimPrefix(arg, "-out=")
+		}
+		if arg == "-out" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// Add the -out argument to the plan command of the module (right after the command) if it is not already specified
+func setPlanFileArg(module *TerraformModule, planFile string) {
+	args := module.TerragruntOptions.TerraformCliArgs
+	if planFileArg(args) != "" || len(args) == 0 {
+		return
+	}
+	module.TerragruntOptions.TerraformCliArgs = append([]string{args[0], "-out=" + planFile}, args[1:]...)
+}
+
+// Render the saved plan as JSON (the command is executed in the folder where the plan has been made)
+func showPlanAsJSON(module TerraformModule, planFile string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := shell.NewTFCmd(module.TerragruntOptions).Args("show", "-json", planFile)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Build the summary from the plan rendered as JSON by `terraform show -json`
+func extractSummaryResultFromJSONPlan(content []byte) (string, int, planChanges, error) {
+	var plan jsonPlan
+	if err := json.Unmarshal(content, &plan); err != nil {
+		return "", 0, planChanges{}, errors.WithStackTrace(err)
+	}
+	if plan.FormatVersion == "" {
+		return "", 0, planChanges{}, errors.WithStackTrace(fmt.Errorf("The JSON plan has no format_version"))
+	}
+
+	var changes planChanges
+	for _, resource := range plan.ResourceChanges {
+		var action string
+		switch strings.Join(resource.Change.Actions, ",") {
+		case "create":
+			action = "create"
+			changes.Add++
+		case "update":
+			action = "update"
+			changes.Change++
+		case "delete":
+			action = "delete"
+			changes.Destroy++
+		case "delete,create", "create,delete":
+			action = "replace"
+			changes.Add++
+			changes.Destroy++
+			changes.Replace++
+		default:
+			// no-op and read actions do not change the infrastructure
+			continue
+		}
+		changes.Resources = append(changes.Resources, resourceChange{resource.Address, action})
+	}
+
+	if len(changes.Resources) == 0 {
+		return "No change", 0, changes, nil
+	}
+	message := fmt.Sprintf("%d to add, %d to change, %d to destroy.", changes.Add, changes.Change, changes.Destroy)
+	if changes.Replace > 0 {
+		message += fmt.Sprintf(" (%d to replace)", changes.Replace)
+	}
+	return message, changes.Add + changes.Change + changes.Destroy, changes, nil
+}
+
 // Parse the output message to extract a summary
-func extractSummaryResultFromPlan(output string) (string, int) {
+func extractSummaryResultFromPlan(output string) (string, int, planChanges) {
 	const noChange = "No changes. Infrastructure is up-to-date."
 	if strings.Contains(output, noChange) {
-		return "No change", 0
+		return "No change", 0, planChanges{}
 	}
 
 	result := planResultRegex.FindStringSubmatch(output)
 	if len(result) == 0 {
-		return "Unable to determine the plan status", -1
+		return "Unable to determine the plan status", -1, planChanges{}
 	}
 
 	// Count the total number of changes
-	sum := 0
-	for _, value := range result[1:] {
-		count, _ := strconv.Atoi(value)
-		sum += count
+	var counts [3]int
+	for i, value := range result[1:] {
+		counts[i], _ = strconv.Atoi(value)
 	}
-	if sum != 0 {
-		return result[0], sum
+	changes := planChanges{Add: counts[0], Change: counts[1], Destroy: counts[2]}
+	if sum := changes.Add + changes.Change + changes.Destroy; sum != 0 {
+		return result[0], sum, changes
 	}
 
 	// Sometimes, terraform returns 0 add, 0 change and 0 destroy. We return a more explicit message
-	return "No effective change", 0
+	return "No effective change", 0, changes
 }
 
 // This is a specialized version of MultiError type
@@ -0,0 +1,252 @@
+package configstack
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The name of the file describing the plans saved by plan-all in --terragrunt-plan-dir
+const planManifestFile = "manifest.json"
+
+const planManifestVersion = 1
+
+// The description of the plans saved by plan-all
+type planManifest struct {
+	Version int                   `json:"version"`
+	Created time.Time             `json:"created"`
+	Stack   string                `json:"stack"`
+	Modules map[string]*savedPlan `json:"modules"` // The saved plans indexed by module path (relative to the stack)
+}
+
+// The plan saved for a module with the fingerprints of its configuration and source at the time of the plan
+type savedPlan struct {
+	Plan       string   `json:"plan"`
+	Config     string   `json:"config"`
+	Source     string   `json:"source"`
+	Violations []string `json:"violations,omitempty"` // The changes on protected resources found in the plan
+}
+
+// Returns the name of the plan file of the module. The name is derived from the module path relative to the stack to
+// ensure that it is stable between plan-all and apply-all.
+func planFileName(stackPath, modulePath string) string {
+	relative, err := filepath.Rel(stackPath, modulePath)
+	if err != nil || strings.HasPrefix(relative, "..") {
+		relative = modulePath
+	}
+	relative = strings.Trim(filepath.ToSlash(relative), "/")
+	if relative == "" || relative == "." {
+		relative = "root"
+	}
+	return strings.Replace(relative, "/", "__", -1) + ".tfplan"
+}
+
+// Returns the path of the module relative to the stack (used as key to identify the module in the manifest and in
+// the JSON outputs)
+func stackRelativePath(stackPath, modulePath string) string {
+	if relative, err := filepath.Rel(stackPath, modulePath); err == nil && !strings.HasPrefix(relative, "..") {
+		return filepath.ToSlash(relative)
+	}
+	return filepath.ToSlash(modulePath)
+}
+
+// Prepare the plan folder and add the -out argument to the plan command of each module. The fingerprints of the
+// modules are computed before the plans are made and the previous plans of the modules are removed.
+func (stack *Stack) preparePlanDir(planDir string) (*planManifest, error) {
+	if err := os.MkdirAll(planDir, 0755); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if err := os.Remove(filepath.Join(planDir, planManifestFile)); err != nil && !os.IsNotExist(err) {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	manifest := &planManifest{Version: planManifestVersion, Created: time.Now().UTC(), Stack: stack.Path, Modules: map[string]*savedPlan{}}
+	for _, module := range stack.Modules {
+		plan, err := fingerprintModule(module)
+		if err != nil {
+			return nil, err
+		}
+		plan.Plan = planFileName(stack.Path, module.Path)
+		planFile := filepath.Join(planDir, plan.Plan)
+		if err := os.Remove(planFile); err != nil && !os.IsNotExist(err) {
+			return nil, errors.WithStackTrace(err)
+		}
+		manifest.Modules[stackRelativePath(stack.Path, module.Path)] = &plan
+		setPlanFileArg(module, planFile)
+	}
+	return manifest, nil
+}
+
+// Write the manifest in the plan folder. Only the modules for which terraform has actually written the plan file are
+// kept in the manifest with the protection violations found in their plan.
+func (manifest *planManifest) save(planDir string, results []moduleResult) error {
+	for key, plan := range manifest.Modules {
+		if !util.FileExists(filepath.Join(planDir, plan.Plan)) {
+			delete(manifest.Modules, key)
+		}
+	}
+	for _, result := range results {
+		if plan := manifest.Modules[stackRelativePath(manifest.Stack, result.Module.Path)]; plan != nil {
+			plan.Violations = result.Violations
+		}
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(ioutil.WriteFile(filepath.Join(planDir, planManifestFile), content, 0644))
+}
+
+// Read the manifest written by plan-all in the plan folder
+func loadPlanManifest(planDir string) (*planManifest, error) {
+	content, err := ioutil.ReadFile(filepath.Join(planDir, planManifestFile))
+	if os.IsNotExist(err) {
+		return nil, errors.WithStackTrace(PlanManifestNotFound(planDir))
+	} else if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var manifest planManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, errors.WithStackTrace(fmt.Errorf("Invalid plan manifest %s: %v", filepath.Join(planDir, planManifestFile), err))
+	}
+	if manifest.Version != planManifestVersion {
+		return nil, errors.WithStackTrace(fmt.Errorf("Unsupported version %d of plan manifest %s", manifest.Version, filepath.Join(planDir, planManifestFile)))
+	}
+	return &manifest, nil
+}
+
+// Returns the path of the plan saved for the module or an error if there is no plan, if the module changed since or
+// if the plan would destroy protected resources (unless allowDestroy is set)
+func (manifest *planManifest) check(planDir, stackPath string, module *TerraformModule, allowDestroy bool) (string, error) {
+	plan := manifest.Modules[stackRelativePath(stackPath, module.Path)]
+	if plan == nil || !util.FileExists(filepath.Join(planDir, plan.Plan)) {
+		return "", SavedPlanNotFound{module}
+	}
+
+	current, err := fingerprintModule(module)
+	if err != nil {
+		return "", err
+	}
+	if current.Config != plan.Config {
+		return "", SavedPlanOutdated{module, "configuration"}
+	}
+	if current.Source != plan.Source {
+		return "", SavedPlanOutdated{module, "source"}
+	}
+	if len(plan.Violations) > 0 && !allowDestroy {
+		return "", ProtectedResourcesViolation{module, plan.Violations}
+	}
+	return filepath.Join(planDir, plan.Plan), nil
+}
+
+// Compute the fingerprints of the configuration files of the module and of its source. The source includes the files
+// in the module folder, the local terraform source and the local import_files sources.
+func fingerprintModule(module *TerraformModule) (result savedPlan, err error) {
+	configHash := sha1.New()
+	configFiles := append([]string{}, module.Config.Files()...)
+	sort.Strings(configFiles)
+	for _, file := range configFiles {
+		if err = hashFile(configHash, filepath.ToSlash(file), file); err != nil {
+			return
+		}
+	}
+
+	sourceHash := sha1.New()
+	if module.Config.Terraform != nil {
+		fmt.Fprintf(sourceHash, "source=%s\n", module.Config.Terraform.Source)
+	}
+	if err = hashFolder(sourceHash, module.Path, false); err != nil {
+		return
+	}
+	folders := module.Config.ImportFilesSources()
+	if source := localTerraformSource(module); source != "" {
+		folders = append(folders, source)
+	}
+	for _, folder := range folders {
+		if err = hashFolder(sourceHash, folder, true); err != nil {
+			return
+		}
+	}
+
+	result.Config = base64.RawURLEncoding.EncodeToString(configHash.Sum(nil))
+	result.Source = base64.RawURLEncoding.EncodeToString(sourceHash.Sum(nil))
+	return
+}
+
+// Add the name and the content of the file to the hash (only the name if the file does not exist)
+func hashFile(hash hash.Hash, name, file string) error {
+	fmt.Fprintf(hash, "file=%s\n", name)
+	content, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	fmt.Fprintf(hash, "size=%d\n", len(content))
+	hash.Write(content)
+	return nil
+}
+
+// Add the files of the folder to the hash, the hidden files and folders (i.e. .terraform) are ignored
+func hashFolder(hash hash.Hash, folder string, recursive bool) error {
+	return filepath.Walk(folder, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if path == folder {
+			return nil
+		}
+		if info.IsDir() {
+			if !recursive || strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(info.Name(), ".") || !info.Mode().IsRegular() {
+			return nil
+		}
+		relative, _ := filepath.Rel(folder, path)
+		return hashFile(hash, filepath.ToSlash(relative), path)
+	})
+}
+
+// Custom error types
+
+// PlanManifestNotFound is the error returned by apply-all when there is no plan manifest in --terragrunt-plan-dir
+type PlanManifestNotFound string
+
+func (err PlanManifestNotFound) Error() string {
+	return fmt.Sprintf("No saved plans found in %s, run plan-all with --terragrunt-plan-dir first", string(err))
+}
+
+// SavedPlanNotFound is the error returned by apply-all for a module that has no saved plan
+type SavedPlanNotFound struct {
+	Module *TerraformModule
+}
+
+func (err SavedPlanNotFound) Error() string {
+	return fmt.Sprintf("There is no saved plan for module %s", util.GetPathRelativeToWorkingDirMax(err.Module.Path, 3))
+}
+
+// SavedPlanOutdated is the error returned by apply-all for a module that changed since its plan has been saved
+type SavedPlanOutdated struct {
+	Module  *TerraformModule
+	Changed string
+}
+
+func (err SavedPlanOutdated) Error() string {
+	return fmt.Sprintf("The %s of module %s changed since its plan has been saved, run plan-all again", err.Changed, util.GetPathRelativeToWorkingDirMax(err.Module.Path, 3))
+}
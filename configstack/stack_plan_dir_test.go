@@ -0,0 +1,86 @@
+package configstack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanFileName(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		modulePath string
+		expected   string
+	}{
+		{"/stack", "root.tfplan"},
+		{"/stack/a", "a.tfplan"},
+		{"/stack/a/b", "a__b.tfplan"},
+		{"/other/c", "other__c.tfplan"},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, planFileName("/stack", testCase.modulePath), "For %s", testCase.modulePath)
+	}
+}
+
+func TestSavedPlans(t *testing.T) {
+	t.Parallel()
+
+	stackPath := createTempFolder(t)
+	defer os.RemoveAll(stackPath)
+	planDir := filepath.Join(stackPath, ".plans")
+
+	newModule := func(path string) *TerraformModule {
+		modulePath := filepath.Join(stackPath, path)
+		createDirIfNotExist(t, modulePath)
+		assert.Nil(t, ioutil.WriteFile(filepath.Join(modulePath, "main.tf"), []byte("# "+path), 0644))
+		terragruntOptions := mockOptions.Clone(filepath.Join(modulePath, "terraform.tfvars"))
+		terragruntOptions.TerraformCliArgs = []string{"plan", "-input=false"}
+		return &TerraformModule{Path: modulePath, TerragruntOptions: terragruntOptions}
+	}
+	moduleA, moduleB := newModule("a"), newModule("a/b")
+	stack := &Stack{Path: stackPath, Modules: []*TerraformModule{moduleA, moduleB}}
+
+	manifest, err := stack.preparePlanDir(planDir)
+	assert.Nil(t, err)
+	planA := filepath.Join(planDir, "a.tfplan")
+	assert.Equal(t, []string{"plan", "-out=" + planA, "-input=false"}, moduleA.TerragruntOptions.TerraformCliArgs)
+
+	// Only the plan of module a has been written
+	assert.Nil(t, ioutil.WriteFile(planA, []byte("plan"), 0644))
+	assert.Nil(t, manifest.save(planDir, nil))
+
+	loaded, err := loadPlanManifest(planDir)
+	assert.Nil(t, err)
+	assert.Len(t, loaded.Modules, 1)
+
+	planFile, err := loaded.check(planDir, stackPath, moduleA, false)
+	assert.Nil(t, err)
+	assert.Equal(t, planA, planFile)
+
+	_, err = loaded.check(planDir, stackPath, moduleB, false)
+	assert.IsType(t, SavedPlanNotFound{}, err)
+
+	// The change of a file in the module folder makes the plan outdated, but not the change in a sub folder
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(moduleB.Path, "main.tf"), []byte("# changed"), 0644))
+	_, err = loaded.check(planDir, stackPath, moduleA, false)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(moduleA.Path, "main.tf"), []byte("# changed"), 0644))
+	_, err = loaded.check(planDir, stackPath, moduleA, false)
+	assert.Equal(t, SavedPlanOutdated{moduleA, "source"}, err)
+}
+
+func TestLoadPlanManifestNotFound(t *testing.T) {
+	t.Parallel()
+
+	planDir := createTempFolder(t)
+	defer os.RemoveAll(planDir)
+
+	_, err := loadPlanManifest(planDir)
+	assert.Equal(t, PlanManifestNotFound(planDir), errors.Unwrap(err))
+}
@@ -0,0 +1,105 @@
+package configstack
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractSummaryResultFromPlan(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		output   string
+		message  string
+		count    int
+		expected planChanges
+	}{
+		{"No changes. Infrastructure is up-to-date.", "No change", 0, planChanges{}},
+		{"Plan: 1 to add, 2 to change, 3 to destroy.", "1 to add, 2 to change, 3 to destroy.", 6, planChanges{Add: 1, Change: 2, Destroy: 3}},
+		{"Plan: 0 to add, 0 to change, 0 to destroy.", "No effective change", 0, planChanges{}},
+		{"Unknown output", "Unable to determine the plan status", -1, planChanges{}},
+	}
+
+	for _, testCase := range testCases {
+		message, count, changes := extractSummaryResultFromPlan(testCase.output)
+		assert.Equal(t, testCase.message, message, "For %s", testCase.output)
+		assert.Equal(t, testCase.count, count, "For %s", testCase.output)
+		assert.Equal(t, testCase.expected, changes, "For %s", testCase.output)
+	}
+}
+
+func TestExtractSummaryResultFromJSONPlan(t *testing.T) {
+	t.Parallel()
+
+	plan := `{
+		"format_version": "0.1",
+		"resource_changes": [
+			{"address": "aws_instance.new", "change": {"actions": ["create"]}},
+			{"address": "aws_instance.same", "change": {"actions": ["no-op"]}},
+			{"address": "data.aws_ami.ami", "change": {"actions": ["read"]}},
+			{"address": "aws_instance.updated", "change": {"actions": ["update"]}},
+			{"address": "aws_instance.replaced", "change": {"actions": ["delete", "create"]}},
+			{"address": "aws_instance.deleted", "change": {"actions": ["delete"]}}
+		]
+	}`
+
+	message, count, changes, err := extractSummaryResultFromJSONPlan([]byte(plan))
+	assert.Nil(t, err)
+	assert.Equal(t, "2 to add, 1 to change, 2 to destroy. (1 to replace)", message)
+	assert.Equal(t, 5, count)
+	assert.Equal(t, planChanges{
+		Add: 2, Change: 1, Destroy: 2, Replace: 1,
+		Resources: []resourceChange{
+			{"aws_instance.new", "create"},
+			{"aws_instance.updated", "update"},
+			{"aws_instance.replaced", "replace"},
+			{"aws_instance.deleted", "delete"},
+		},
+	}, changes)
+
+	message, count, _, err = extractSummaryResultFromJSONPlan([]byte(`{"format_version": "0.1"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "No change", message)
+	assert.Equal(t, 0, count)
+
+	// The output of old terraform versions is not JSON
+	_, _, _, err = extractSummaryResultFromJSONPlan([]byte("Refreshing Terraform state in-memory prior to plan..."))
+	assert.NotNil(t, err)
+}
+
+func TestPlanFileArg(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", planFileArg([]string{"plan", "-input=false"}))
+	assert.Equal(t, "/tmp/a.tfplan", planFileArg([]string{"plan", "-out=/tmp/a.tfplan"}))
+	assert.Equal(t, "/tmp/a.tfplan", planFileArg([]string{"plan", "-out", "/tmp/a.tfplan", "-input=false"}))
+
+	module := &TerraformModule{TerragruntOptions: mockOptions.Clone(mockOptions.TerragruntConfigPath)}
+	module.TerragruntOptions.TerraformCliArgs = []string{"plan", "-input=false"}
+	setPlanFileArg(module, "/tmp/a.tfplan")
+	assert.Equal(t, []string{"plan", "-out=/tmp/a.tfplan", "-input=false"}, module.TerragruntOptions.TerraformCliArgs)
+	setPlanFileArg(module, "/tmp/b.tfplan")
+	assert.Equal(t, "/tmp/a.tfplan", planFileArg(module.TerragruntOptions.TerraformCliArgs))
+}
+
+func TestSupportsJSONPlan(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := mockOptions.Clone(mockOptions.TerragruntConfigPath)
+	assert.False(t, terragruntOptions.SupportsJSONPlan(), "Unknown version")
+
+	for _, testCase := range []struct {
+		version  string
+		expected bool
+	}{
+		{"0.9.3", false},
+		{"0.11.14", false},
+		{"0.12.0", true},
+		{"1.5.7", true},
+	} {
+		terragruntOptions.TerraformVersion = version.Must(version.NewVersion(testCase.version))
+		assert.Equal(t, testCase.expected, terragruntOptions.SupportsJSONPlan(), "For %s", testCase.version)
+	}
+}
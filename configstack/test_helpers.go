@@ -149,6 +149,13 @@ func assertConfigsEqual(t *testing.T, expected config.TerragruntConfig, actual c
 	assert.Equal(t, expected.RemoteState, actual.RemoteState, messageAndArgs...)
 	assert.Equal(t, expected.Terraform, actual.Terraform, messageAndArgs...)
 	assert.Equal(t, expected.Uniqueness, actual.Uniqueness, messageAndArgs...)
+	assert.Equal(t, expected.Timeout, actual.Timeout, messageAndArgs...)
+	assert.Equal(t, expected.Priority, actual.Priority, messageAndArgs...)
+	assert.Equal(t, expected.Weight, actual.Weight, messageAndArgs...)
+	assert.Equal(t, expected.Group, actual.Group, messageAndArgs...)
+	assert.Equal(t, expected.GroupLimits, actual.GroupLimits, messageAndArgs...)
+	assert.Equal(t, expected.Protect, actual.Protect, messageAndArgs...)
+	assert.Equal(t, expected.DependencyList, actual.DependencyList, messageAndArgs...)
 }
 
 // Return the absolute path for the given path
@@ -0,0 +1,71 @@
+package options
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// CommandSignals keeps track of the signal channels (see shell.SignalsForwarder) of the commands started on behalf of
+// a module. This allows Terragrunt to interrupt all the commands of a module (e.g. when its timeout is exceeded).
+type CommandSignals struct {
+	mutex       sync.Mutex
+	channels    map[chan os.Signal]bool
+	interrupted bool
+}
+
+// NewCommandSignals returns a new empty CommandSignals
+func NewCommandSignals() *CommandSignals {
+	return &CommandSignals{channels: map[chan os.Signal]bool{}}
+}
+
+// Register adds the signal channel of a running command
+func (signals *CommandSignals) Register(channel chan os.Signal) {
+	if signals == nil {
+		return
+	}
+	signals.mutex.Lock()
+	defer signals.mutex.Unlock()
+	signals.channels[channel] = true
+}
+
+// Unregister removes the signal channel of a command that is now completed
+func (signals *CommandSignals) Unregister(channel chan os.Signal) {
+	if signals == nil {
+		return
+	}
+	signals.mutex.Lock()
+	defer signals.mutex.Unlock()
+	delete(signals.channels, channel)
+}
+
+// Interrupt sends the given signals to all the running commands. Once interrupted, no new command should be started.
+// It returns the number of commands that received the signals.
+func (signals *CommandSignals) Interrupt(toSend ...os.Signal) int {
+	if signals == nil {
+		return 0
+	}
+	signals.mutex.Lock()
+	defer signals.mutex.Unlock()
+	signals.interrupted = true
+	for channel := range signals.channels {
+		for _, signal := range toSend {
+			select {
+			case channel <- signal:
+			case <-time.After(time.Second):
+				// The command is not listening anymore
+			}
+		}
+	}
+	return len(signals.channels)
+}
+
+// Interrupted returns true if the commands have been interrupted
+func (signals *CommandSignals) Interrupted() bool {
+	if signals == nil {
+		return false
+	}
+	signals.mutex.Lock()
+	defer signals.mutex.Unlock()
+	return signals.interrupted
+}
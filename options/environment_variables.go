@@ -13,6 +13,10 @@ const (
 	EnvApplyTemplate    = "TERRAGRUNT_TEMPLATE"          // Used to configure whether or not go template should be applied on terraform (.tf and .tfvars) file
 	EnvTemplatePatterns = "TERRAGRUNT_TEMPLATE_PATTERNS" // Used to configure the extra files (other than .tf) that should be processed by go template
 	EnvBootConfigs      = "TERRAGRUNT_BOOT_CONFIGS"      // Used to set defaults configuration when launching terragrunt
+	EnvReport           = "TERRAGRUNT_REPORT"            // Used to configure the file where the report of -all operations is written (optional)
+	EnvIncludeDir       = "TERRAGRUNT_INCLUDE_DIR"       // Used to configure the glob patterns of the folders processed by -all operations (optional, separated by path list separator)
+	EnvExcludeDir       = "TERRAGRUNT_EXCLUDE_DIR"       // Used to configure the glob patterns of the folders ignored by -all operations (optional, separated by path list separator)
+	EnvCacheTTL         = "TERRAGRUNT_CACHE_TTL"         // Used to configure how long the results of the AWS calls made by the interpolation functions are kept on disk (optional, default not kept)
 )
 
 // All environment variables that are published during Terragrunt execution to share current context during shell execution
@@ -16,6 +16,7 @@ import (
 	"github.com/coveo/gotemplate/utils"
 	"github.com/gruntwork-io/terragrunt/errors"
 	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/go-version"
 	"github.com/op/go-logging"
 	"gopkg.in/yaml.v2"
 )
@@ -28,6 +29,9 @@ type TerragruntOptions struct {
 	// Location of the terraform binary
 	TerraformPath string
 
+	// The version of the terraform binary (nil until it has been checked)
+	TerraformVersion *version.Version
+
 	// Whether we should prompt the user for confirmation or always assume "yes"
 	NonInteractive bool
 
@@ -93,6 +97,80 @@ type TerragruntOptions struct {
 	// Indicates the number of concurrent workers
 	NbWorkers int
 
+	// If set, a machine-readable report of the *-all commands is written to this file (JUnit XML if the file
+	// extension is .xml, JSON otherwise)
+	ReportFile string
+
+	// If set, the *-all commands resume the specified run (identified by its TERRAGRUNT_RUN_ID) by skipping the
+	// modules that have already been successfully completed during that run
+	ResumeRunID string
+
+	// If set, the *-all commands only process the modules affected by the files changed since this git reference
+	ChangedSince string
+
+	// If set, the modules depending on the modules affected by the changes are also processed (see ChangedSince)
+	ChangedWithDependents bool
+
+	// If set, the *-all commands only process the modules whose folder (relative to the working dir) matches one of
+	// these glob patterns
+	IncludeDirs []string
+
+	// The *-all commands do not process the modules whose folder (relative to the working dir) matches one of these
+	// glob patterns
+	ExcludeDirs []string
+
+	// If set, the dependencies of the modules selected by IncludeDirs/ExcludeDirs are also processed
+	IncludeDependencies bool
+
+	// The maximum duration of each module processed by the *-all commands (0 = no timeout). It could be overridden
+	// by the timeout attribute of the terragrunt configuration.
+	ModuleTimeout time.Duration
+
+	// If set, the modules processed by the *-all commands are interrupted when this deadline is reached and the
+	// remaining ones are not started
+	Deadline time.Time
+
+	// The maximum number of modules of each concurrency group that could run simultaneously (in addition to
+	// NbWorkers). These limits have precedence over the concurrency_limits defined in the configuration files.
+	GroupLimits map[string]int
+
+	// If set, the *-all commands stop starting new modules as soon as a module fails
+	FailFast bool
+
+	// If set, plan-all saves the plan of each module in this folder and apply-all applies the saved plans
+	PlanDir string
+
+	// The saved plan applied by the command, it is passed to terraform after all the other arguments
+	PlanFile string
+
+	// If set, apply first saves the plan of the changes and calls this function to validate it before applying it from
+	// the same working directory (i.e. to refuse the plans destroying protected resources)
+	ValidatePlan func(terragruntOptions *TerragruntOptions, planFile string) error
+
+	// If set, apply-all is allowed to destroy or replace the resources protected by the protect blocks
+	AllowDestroy bool
+
+	// If set, the dependencies between the modules are also inferred from their terraform_remote_state data sources
+	InferDependencies bool
+
+	// If set, the unknown attributes and the invalid values of the terragrunt block are reported as errors instead of
+	// warnings (the deprecated .terragrunt files are never checked strictly). It is enabled by the command line unless
+	// --terragrunt-non-strict-config is specified.
+	StrictConfig bool
+
+	// If set, the *-all commands also interrupt the running modules as soon as a module fails (implies FailFast)
+	FailFastInterrupt bool
+
+	// The signal channels of the commands currently running on behalf of a module (nil if not tracked)
+	Signals *CommandSignals
+
+	// The cache used to memoize the expensive calls made while processing the configurations (shared by the clones)
+	Cache *RunCache
+
+	// The configuration files being read by read_terragrunt_config on behalf of this configuration (used to detect the
+	// configurations reading each other)
+	ReadingConfigs []string
+
 	// The list of files (should be only one) where to save files if save_variables() has been invoked by the user
 	deferredSaveList map[string]bool
 
@@ -122,6 +200,7 @@ func NewTerragruntOptions(terragruntConfigPath string) *TerragruntOptions {
 		Env:                  make(map[string]string),
 		Variables:            make(map[string]Variable),
 		DownloadDir:          downloadDir,
+		Cache:                NewRunCache("", 0),
 		Writer:               os.Stdout,
 		ErrWriter:            os.Stderr,
 		RunTerragrunt: func(terragruntOptions *TerragruntOptions) error {
@@ -353,6 +432,7 @@ const (
 	UndefinedSource VariableSource = iota
 	Default
 	ConfigVarFile
+	ParameterStore // Variables imported from the AWS SSM parameter store (import_variables or discover_all)
 	VarFile
 	VarFileExplicit
 	VarParameter
@@ -360,5 +440,13 @@ const (
 	VarParameterExplicit
 )
 
+// The minimum version of terraform able to render the saved plans as JSON (terraform show -json)
+var jsonPlanVersion = version.Must(version.NewVersion("0.12.0"))
+
+// SupportsJSONPlan returns true if the version of terraform is known and able to render the saved plans as JSON
+func (terragruntOptions *TerragruntOptions) SupportsJSONPlan() bool {
+	return terragruntOptions.TerraformVersion != nil && !terragruntOptions.TerraformVersion.LessThan(jsonPlanVersion)
+}
+
 // ErrRunTerragruntCommandNotSet is a custom error
 var ErrRunTerragruntCommandNotSet = fmt.Errorf("The RunTerragrunt option has not been set on this TerragruntOptions object")
@@ -0,0 +1,144 @@
+package options
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// RunCache memoizes the results of expensive calls (i.e. the AWS calls made by the interpolation functions) during a
+// run. It is shared by all the clones of the options and it is safe for concurrent use. The persistent results are also
+// saved on disk to be reused by the following runs until their time to live expires.
+type RunCache struct {
+	mutex   sync.Mutex
+	entries map[string]*runCacheEntry
+	secrets map[string]bool // The secret values resolved during the run, they are redacted from the logs
+	folder  string          // The folder where the persistent results are saved
+	ttl     time.Duration   // The time to live of the persistent results (they are not saved on disk if 0)
+}
+
+type runCacheEntry struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// The content of a result saved on disk
+type persistedResult struct {
+	Expires time.Time   `json:"expires"`
+	Value   interface{} `json:"value"`
+}
+
+// NewRunCache returns a new empty RunCache
+func NewRunCache(folder string, ttl time.Duration) *RunCache {
+	return &RunCache{entries: map[string]*runCacheEntry{}, secrets: map[string]bool{}, folder: folder, ttl: ttl}
+}
+
+// Memoize returns the result associated to the key or calls compute to get it. The concurrent calls with the same key
+// wait for the result of the first one instead of calling compute again. The errors are not cached. If persistent is
+// set, the result is also saved on disk (it should not be set for results containing secrets).
+func (cache *RunCache) Memoize(key string, persistent bool, compute func() (interface{}, error)) (interface{}, error) {
+	if cache == nil {
+		return compute()
+	}
+
+	cache.mutex.Lock()
+	entry, exist := cache.entries[key]
+	if !exist {
+		entry = &runCacheEntry{done: make(chan struct{})}
+		cache.entries[key] = entry
+	}
+	cache.mutex.Unlock()
+
+	if exist {
+		<-entry.done
+		return entry.value, entry.err
+	}
+	defer close(entry.done)
+
+	persistent = persistent && cache.ttl > 0 && cache.folder != ""
+	if persistent {
+		if value, found := cache.load(key); found {
+			entry.value = value
+			return entry.value, nil
+		}
+	}
+
+	entry.value, entry.err = compute()
+	if entry.err != nil {
+		// The next call will retry
+		cache.mutex.Lock()
+		delete(cache.entries, key)
+		cache.mutex.Unlock()
+	} else if persistent {
+		cache.save(key, entry.value)
+	}
+	return entry.value, entry.err
+}
+
+// The secret values shorter than this length are not redacted since they would also redact unrelated text (i.e. 1,
+// true or dev)
+const minRedactedSecretLength = 6
+
+// AddSecret registers a secret value that must be redacted from the logs during the run
+func (cache *RunCache) AddSecret(value string) {
+	if cache == nil || len(value) < minRedactedSecretLength {
+		return
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.secrets[value] = true
+}
+
+// RedactSecrets replaces the secret values registered during the run by *** in the text
+func (cache *RunCache) RedactSecrets(text string) string {
+	if cache == nil {
+		return text
+	}
+	cache.mutex.Lock()
+	values := make([]string, 0, len(cache.secrets))
+	for value := range cache.secrets {
+		values = append(values, value)
+	}
+	cache.mutex.Unlock()
+
+	// The longest values are replaced first to avoid leaving a part of a secret that contains another one
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	for _, value := range values {
+		text = strings.Replace(text, value, "***", -1)
+	}
+	return text
+}
+
+func (cache *RunCache) path(key string) string {
+	return filepath.Join(cache.folder, util.EncodeBase64Sha1(key)+".json")
+}
+
+// Returns the result saved on disk if it is not expired
+func (cache *RunCache) load(key string) (interface{}, bool) {
+	content, err := ioutil.ReadFile(cache.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var result persistedResult
+	if json.Unmarshal(content, &result) != nil || time.Now().After(result.Expires) {
+		return nil, false
+	}
+	return result.Value, true
+}
+
+// Saves the result on disk, the errors are ignored since the result will simply be computed again by the next run
+func (cache *RunCache) save(key string, value interface{}) {
+	content, err := json.Marshal(persistedResult{time.Now().Add(cache.ttl), value})
+	if err != nil || os.MkdirAll(cache.folder, 0700) != nil {
+		return
+	}
+	ioutil.WriteFile(cache.path(key), content, 0600)
+}
@@ -0,0 +1,98 @@
+package options
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCacheMemoize(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	compute := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond)
+		return "123456789012", nil
+	}
+
+	// The clones share the same cache and the concurrent calls are only computed once
+	terragruntOptions := NewTerragruntOptionsForTest("terraform.tfvars")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(clone *TerragruntOptions) {
+			defer wg.Done()
+			value, err := clone.Cache.Memoize("get_aws_account_id()", true, compute)
+			assert.NoError(t, err)
+			assert.Equal(t, "123456789012", value)
+		}(terragruntOptions.Clone(fmt.Sprintf("module%d/terraform.tfvars", i)))
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestRunCacheErrorsAreNotCached(t *testing.T) {
+	t.Parallel()
+
+	cache := NewRunCache("", 0)
+	_, err := cache.Memoize("key", false, func() (interface{}, error) { return nil, fmt.Errorf("failed") })
+	assert.Error(t, err)
+
+	value, err := cache.Memoize("key", false, func() (interface{}, error) { return "value", nil })
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+}
+
+func TestRunCachePersistence(t *testing.T) {
+	t.Parallel()
+
+	folder, err := ioutil.TempDir("", "run-cache")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(folder)
+
+	failure := func() (interface{}, error) { return nil, fmt.Errorf("should not be called") }
+
+	_, err = NewRunCache(folder, time.Hour).Memoize("persistent", true, func() (interface{}, error) { return "value", nil })
+	assert.NoError(t, err)
+	value, err := NewRunCache(folder, time.Hour).Memoize("persistent", true, failure)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	// The results that are not persistent are not saved
+	_, err = NewRunCache(folder, time.Hour).Memoize("secret", false, func() (interface{}, error) { return "value", nil })
+	assert.NoError(t, err)
+	_, err = NewRunCache(folder, time.Hour).Memoize("secret", false, failure)
+	assert.Error(t, err)
+
+	// The expired results are computed again
+	_, err = NewRunCache(folder, time.Nanosecond).Memoize("expired", true, func() (interface{}, error) { return "old", nil })
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	value, err = NewRunCache(folder, time.Hour).Memoize("expired", true, func() (interface{}, error) { return "new", nil })
+	assert.NoError(t, err)
+	assert.Equal(t, "new", value)
+}
+
+func TestRunCacheRedactSecrets(t *testing.T) {
+	t.Parallel()
+
+	cache := NewRunCache("", 0)
+	for _, secret := range []string{"1", "true", "dev", "db-password", "password"} {
+		cache.AddSecret(secret)
+	}
+
+	// The short values are not redacted and the longest secrets are redacted first
+	assert.Equal(t, "env=dev, enabled=true, count=1, user=***, admin=***", cache.RedactSecrets("env=dev, enabled=true, count=1, user=db-password, admin=password"))
+
+	// The secrets are only redacted during the run that resolved them
+	assert.Equal(t, "user=db-password", NewRunCache("", 0).RedactSecrets("user=db-password"))
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/coveo/gotemplate/collections"
 	"github.com/coveo/gotemplate/utils"
@@ -126,16 +127,20 @@ func (c CommandContext) Run() error {
 	logger := iif(c.Stdout == c.options.Writer, c.log.Notice, c.log.Info).(func(...interface{}))
 
 	if c.command == c.options.TerraformPath {
-		const noColor = "-no-color"
-		if util.ListContainsElement(c.options.TerraformCliArgs, noColor) {
-			// If the user specified -no-color, we should respect it in intermediate calls too
-			c.args = append(c.args, noColor)
-		}
 		// Terragrunt can run some commands (such as terraform remote config) before running the actual terraform
 		// command requested by the user. The output of these other commands should not end up on stdout as this
-		// breaks scripts relying on terraform's output.
+		// breaks scripts relying on terraform's output (unless the caller captures it).
 		if !reflect.DeepEqual(collections.ToInterfaces(c.options.TerraformCliArgs...), c.args) {
-			c.Stdout = c.Stderr
+			if c.Stdout == c.options.Writer {
+				c.Stdout = c.Stderr
+			}
+
+			const noColor = "-no-color"
+			if util.ListContainsElement(c.options.TerraformCliArgs, noColor) && len(c.args) > 0 {
+				// If the user specified -no-color, we should respect it in intermediate calls too (it is added right
+				// after the command since the flags are not parsed after the positional arguments, i.e. a saved plan)
+				c.args = append([]interface{}{c.args[0], noColor}, c.args[1:]...)
+			}
 		}
 	}
 
@@ -153,6 +158,11 @@ func (c CommandContext) Run() error {
 
 	var finalStatus error
 	for try := 0; try <= c.retries; try++ {
+		if c.options.Signals.Interrupted() {
+			// The module has been interrupted (i.e. timeout), we should not start new commands
+			return errors.WithStackTrace(CommandInterrupted(c.command))
+		}
+
 		cmd, tempFile, err := utils.GetCommandFromString(c.command, c.args...)
 		if err != nil {
 			return errors.WithStackTrace(err)
@@ -190,6 +200,7 @@ func (c CommandContext) Run() error {
 
 		signalChannel := NewSignalsForwarder(forwardSignals, cmd, c.log, cmdChannel)
 		defer signalChannel.Close()
+		c.options.Signals.Register(signalChannel)
 
 		if c.expectedStatements != nil && c.completedStatements != nil {
 			finalStatus = RunCommandToApprove(cmd, c.expectedStatements, c.completedStatements, c.options)
@@ -199,6 +210,7 @@ func (c CommandContext) Run() error {
 		}
 
 		cmdChannel <- finalStatus
+		c.options.Signals.Unregister(signalChannel)
 		if finalStatus == nil {
 			break
 		}
@@ -262,23 +274,49 @@ func NewSignalsForwarder(signals []os.Signal, c *exec.Cmd, logger *logging.Logge
 	signal.Notify(signalChannel, signals...)
 
 	go func() {
+		// The signals received before the command is started are kept and forwarded once its process exists
+		var pending []os.Signal
+		var retry <-chan time.Time
 		for {
 			select {
 			case s := <-signalChannel:
+				pending = append(pending, s)
+			case <-retry:
+			case <-cmdChannel:
+				if len(pending) > 0 {
+					logger.Warningf("Unable to forward signal(s) %v, the command has not been started.", pending)
+				}
+				return
+			}
+
+			if c.Process == nil {
+				if retry == nil {
+					logger.Warningf("The command is not started yet, signal(s) %v will be forwarded once it is started.", pending)
+				}
+				retry = time.After(signalRetryDelay)
+				continue
+			}
+			for _, s := range pending {
 				logger.Warningf("Forward signal %v to terraform.", s)
-				err := c.Process.Signal(s)
-				if err != nil {
+				if err := c.Process.Signal(s); err != nil {
 					logger.Errorf("Error forwarding signal: %v", err)
 				}
-			case <-cmdChannel:
-				return
 			}
+			pending, retry = nil, nil
 		}
 	}()
 
 	return signalChannel
 }
 
+// The delay between the checks of the command process when signals are waiting to be forwarded
+var signalRetryDelay = 100 * time.Millisecond
+
+// ForwardedSignals returns the list of signals that are forwarded to the running commands
+func ForwardedSignals() []os.Signal {
+	return forwardSignals
+}
+
 // Close closes the signal channel
 func (signalChannel *SignalsForwarder) Close() error {
 	signal.Stop(*signalChannel)
@@ -288,3 +326,10 @@ func (signalChannel *SignalsForwarder) Close() error {
 }
 
 var iif = collections.IIf
+
+// CommandInterrupted is the error returned when trying to run a command for a module that has been interrupted
+type CommandInterrupted string
+
+func (command CommandInterrupted) Error() string {
+	return fmt.Sprintf("Command %s not started since the module has been interrupted", string(command))
+}
@@ -1,6 +1,7 @@
 package shell
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/gruntwork-io/terragrunt/options"
@@ -18,3 +19,19 @@ func TestRunShellCommand(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, value, "Usage: terraform")
 }
+
+func TestRunIntermediateCommandWithCapturedOutput(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("")
+	terragruntOptions.TerraformPath = "echo"
+	terragruntOptions.TerraformCliArgs = []string{"apply", "-no-color"}
+
+	// The captured output is kept on stdout and -no-color is added before the positional arguments
+	var stdout, stderr bytes.Buffer
+	cmd := NewTFCmd(terragruntOptions).Args("show", "-json", "apply.tfplan")
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	assert.NoError(t, cmd.Run())
+	assert.Equal(t, "show -no-color -json apply.tfplan\n", stdout.String())
+	assert.Empty(t, stderr.String())
+}
@@ -114,3 +114,24 @@ func TestNewSignalsForwarderMultipleUnix(t *testing.T) {
 	assert.True(t, retCode <= interrupts, "Subprocess received wrong number of signals")
 	assert.Equal(t, retCode, expectedInterrupts, "Subprocess didn't receive multiple signals")
 }
+
+func TestNewSignalsForwarderBeforeStartUnix(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := options.NewTerragruntOptionsForTest("")
+	cmd := exec.Command("sleep", "10")
+
+	cmdChannel := make(chan error)
+	signalChannel := NewSignalsForwarder(forwardSignals, cmd, terragruntOptions.Logger, cmdChannel)
+	defer signalChannel.Close()
+
+	// The signal is received before the command is started, it must be forwarded once the process exists
+	signalChannel <- os.Interrupt
+	time.Sleep(200 * time.Millisecond)
+
+	start := time.Now()
+	err := cmd.Run()
+	cmdChannel <- err
+	assert.Error(t, err)
+	assert.WithinDuration(t, start, time.Now(), 5*time.Second, "Expected the command to be interrupted")
+}